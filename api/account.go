@@ -6,15 +6,39 @@ import (
 	"github.com/Petatron/bank-simulator-backend/token"
 	"github.com/lib/pq"
 	"net/http"
+	"strings"
 
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
 	"github.com/Petatron/bank-simulator-backend/model"
 	"github.com/gin-gonic/gin"
 )
 
-// createAccountRequest defines the body for createAccount API request
+// accountResponse is an account with the balance that is not reserved by pending holds.
+// Balances are in minor units, the decimal fields hold them as decimal strings in the major unit of the currency.
+type accountResponse struct {
+	db.Account
+	AvailableBalance        int64  `json:"available_balance"`
+	BalanceDecimal          string `json:"balance_decimal,omitempty"`
+	AvailableBalanceDecimal string `json:"available_balance_decimal,omitempty"`
+}
+
+// newAccountResponse creates the response of an account with the given amount on hold
+func (server *Server) newAccountResponse(account db.Account, held int64) accountResponse {
+	available := account.Balance - held
+	return accountResponse{
+		Account:                 account,
+		AvailableBalance:        available,
+		BalanceDecimal:          server.formatAmount(account.Balance, account.Currency),
+		AvailableBalanceDecimal: server.formatAmount(available, account.Currency),
+	}
+}
+
+// createAccountRequest defines the body for createAccount API request.
+// A user can open several accounts in the same currency, Type defaults to checking and Nickname is optional.
 type createAccountRequest struct {
 	Currency model.CurrencyType `json:"currency" binding:"required,currency"`
+	Type     model.AccountType  `json:"type" binding:"omitempty,oneof=checking savings loan"`
+	Nickname string             `json:"nickname" binding:"max=50"`
 }
 
 // createAccount implement the API that creates a new account
@@ -24,19 +48,31 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	if err := server.checkCurrency(req.Currency); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
 	// createAccount API rule: A logged-in user can only create an account for themselves
+	accountType := req.Type
+	if accountType == "" {
+		accountType = model.AccountTypeChecking
+	}
+
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
 		Owner:    authPayload.Username,
 		Balance:  0,
 		Currency: string(req.Currency),
+		Type:     string(accountType),
+		Nickname: strings.TrimSpace(req.Nickname),
 	}
 
 	account, err := server.store.CreateAccount(ctx, arg)
 	if err != nil {
 		var pqError *pq.Error
 		if errors.As(err, &pqError) {
+			// The nickname is already used by another account of the user, or the user does not exist
 			switch pqError.Code.Name() {
 			case "unique_violation", "foreign_key_violation":
 				ctx.JSON(http.StatusForbidden, errorResponse(err))
@@ -47,7 +83,7 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, server.newAccountResponse(account, 0))
 }
 
 // getAccountRequest defines the body for getAccount API request
@@ -73,21 +109,25 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		return
 	}
 
-	// getAccount API rule: A logged-in user can only get an account for they own
+	// getAccount API rule: A logged-in user can only get an account they own, unless their role may view any account
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
+	if err := authorizeAccountView(authPayload, account); err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	held, err := server.store.SumActiveHolds(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newAccountResponse(account, held))
 }
 
-// listAccountRequest defines the body for listAccounts API request
+// listAccountRequest defines the query for listAccounts API request, accounts are listed oldest first
 type listAccountRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	pageRequest
 }
 
 // listAccount implements the API that returns a list of accounts
@@ -100,10 +140,15 @@ func (server *Server) listAccount(ctx *gin.Context) {
 
 	// listAccount API rule: A logged-in user can only get a list of accounts that belong to them
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	afterID, err := req.after()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 	arg := db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		Owner:   authPayload.Username,
+		AfterID: afterID,
+		Limit:   req.limit(),
 	}
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
@@ -111,28 +156,83 @@ func (server *Server) listAccount(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	var rsp pageResponse[accountResponse]
+	accounts, rsp.NextCursor = paginate(req.pageRequest, accounts, func(account db.Account) int64 {
+		return account.ID
+	})
+
+	total, err := server.store.CountAccounts(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	rsp.TotalCount = &total
 
-	ctx.JSON(http.StatusOK, accounts)
+	accountIDs := make([]int64, len(accounts))
+	for i, account := range accounts {
+		accountIDs[i] = account.ID
+	}
+	holds, err := server.store.SumActiveHoldsByAccounts(ctx, accountIDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	held := make(map[int64]int64, len(holds))
+	for _, hold := range holds {
+		held[hold.AccountID] = hold.Total
+	}
+	rsp.Items = make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		rsp.Items[i] = server.newAccountResponse(account, held[account.ID])
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
 
-// deleteAccountRequest defines the body for deleteAccount API request
-type deleteAccountRequest struct {
+// closeAccountRequest defines the body for closeAccount API request
+type closeAccountRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-// deleteAccount deletes a specific account based on the given ID
-func (server *Server) deleteAccount(ctx *gin.Context) {
-	var req deleteAccountRequest
+// closeAccount implements the API that closes a specific account based on the given ID.
+// The account is kept with a closed status and timestamp so its history stays intact.
+func (server *Server) closeAccount(ctx *gin.Context) {
+	var req closeAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	err := server.store.DeleteAccount(ctx, req.ID)
+	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.Status(http.StatusOK)
+	// closeAccount API rule: A logged-in user can only close an account they own, unless they are an admin
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authorizeAccountClose(authPayload, account); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// The balance and status are checked again under the row lock by ChangeAccountStatusTx
+	result, err := server.store.ChangeAccountStatusTx(ctx, db.ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    model.AccountStatusClosed,
+		Reason:    "account closed on request",
+		ChangedBy: authPayload.Username,
+	})
+	if err != nil {
+		respondStatusChangeError(ctx, err)
+		return
+	}
+
+	// A closed account has no pending holds, ChangeAccountStatusTx refuses to close it otherwise
+	ctx.JSON(http.StatusOK, server.newAccountResponse(result.Account, 0))
 }
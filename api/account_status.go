@@ -0,0 +1,112 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// accountStatusURI defines the URI parameters of the account status APIs
+type accountStatusURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// updateAccountStatusRequest defines the body for updateAccountStatus API request.
+// BlockCredits only applies when freezing, a frozen account always rejects debits.
+type updateAccountStatusRequest struct {
+	Status       model.AccountStatus `json:"status" binding:"required,oneof=active frozen closed"`
+	BlockCredits bool                `json:"block_credits"`
+	Reason       string              `json:"reason" binding:"required,max=500"`
+}
+
+// updateAccountStatus implements the admin API that freezes, unfreezes or closes an account.
+// Every change is recorded with its reason in the account status audit trail.
+func (server *Server) updateAccountStatus(ctx *gin.Context) {
+	var uri accountStatusURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateAccountStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.ChangeAccountStatusTx(ctx, db.ChangeAccountStatusTxParams{
+		AccountID:    uri.ID,
+		Status:       req.Status,
+		BlockCredits: req.BlockCredits,
+		Reason:       req.Reason,
+		ChangedBy:    authPayload.Username,
+	})
+	if err != nil {
+		respondStatusChangeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// listAccountStatusChangesRequest defines the query for listAccountStatusChanges API request
+type listAccountStatusChangesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listAccountStatusChanges implements the API that returns the status audit trail of an account
+func (server *Server) listAccountStatusChanges(ctx *gin.Context) {
+	var uri accountStatusURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listAccountStatusChangesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	changes, err := server.store.ListAccountStatusChanges(ctx, db.ListAccountStatusChangesParams{
+		AccountID: uri.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, changes)
+}
+
+// respondStatusChangeError responds with the status code matching an error of ChangeAccountStatusTx
+func respondStatusChangeError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, db.ErrAccountClosed), errors.Is(err, db.ErrInvalidStatusChange):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrAccountNotEmpty), errors.Is(err, db.ErrAccountFrozen):
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
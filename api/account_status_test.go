@@ -0,0 +1,380 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("updateAccountStatus API", func() {
+		account := getRandomAccount(util.GetRandomOwnerName())
+		account.Status = string(model.AccountStatusActive)
+
+		frozenAccount := account
+		frozenAccount.Status = string(model.AccountStatusFrozen)
+		frozenAccount.BlockCredits = true
+
+		testCases := []struct {
+			name          string
+			accountID     int64
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:      "OK",
+				accountID: account.ID,
+				body: gin.H{
+					"status":        "frozen",
+					"block_credits": true,
+					"reason":        "suspicious activity",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.ChangeAccountStatusTxParams{
+						AccountID:    account.ID,
+						Status:       model.AccountStatusFrozen,
+						BlockCredits: true,
+						Reason:       "suspicious activity",
+						ChangedBy:    "admin",
+					}
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{Account: frozenAccount}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var result db.ChangeAccountStatusTxResult
+					err := json.Unmarshal(recorder.Body.Bytes(), &result)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.Account).To(Equal(frozenAccount))
+				},
+			},
+
+			{
+				name:      "Teller Forbidden",
+				accountID: account.ID,
+				body: gin.H{
+					"status": "frozen",
+					"reason": "suspicious activity",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "teller", model.RoleTeller, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				},
+			},
+
+			{
+				name:      "Owner Forbidden",
+				accountID: account.ID,
+				body: gin.H{
+					"status": "active",
+					"reason": "unfreeze my account",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, account.Owner, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				},
+			},
+
+			{
+				name:      "Missing Reason",
+				accountID: account.ID,
+				body: gin.H{
+					"status": "frozen",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:      "Invalid Status",
+				accountID: account.ID,
+				body: gin.H{
+					"status": "suspended",
+					"reason": "suspicious activity",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:      "Account Not Found",
+				accountID: account.ID,
+				body: gin.H{
+					"status": "frozen",
+					"reason": "suspicious activity",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{}, sql.ErrNoRows)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name:      "Invalid Status Change",
+				accountID: account.ID,
+				body: gin.H{
+					"status": "active",
+					"reason": "already active",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{}, db.ErrInvalidStatusChange)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name:      "Internal Error",
+				accountID: account.ID,
+				body: gin.H{
+					"status": "frozen",
+					"reason": "suspicious activity",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				// create mock store
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				// start test server and send request
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				data, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				url := fmt.Sprintf("/accounts/%d/status", tc.accountID)
+				request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+
+				// call the server
+				server.router.ServeHTTP(recorder, request)
+				// check the response
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+
+	Context("listAccountStatusChanges API", func() {
+		account := getRandomAccount(util.GetRandomOwnerName())
+		changes := []db.AccountStatusChange{
+			{
+				ID:         util.GetRandomInt(),
+				AccountID:  account.ID,
+				FromStatus: string(model.AccountStatusActive),
+				ToStatus:   string(model.AccountStatusFrozen),
+				Reason:     "suspicious activity",
+				ChangedBy:  "admin",
+			},
+		}
+
+		testCases := []struct {
+			name          string
+			query         string
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:  "OK",
+				query: "page_id=1&page_size=5",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "teller", model.RoleTeller, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					arg := db.ListAccountStatusChangesParams{
+						AccountID: account.ID,
+						Limit:     5,
+						Offset:    0,
+					}
+					store.EXPECT().
+						ListAccountStatusChanges(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(changes, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var gotChanges []db.AccountStatusChange
+					err := json.Unmarshal(recorder.Body.Bytes(), &gotChanges)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(gotChanges).To(Equal(changes))
+				},
+			},
+
+			{
+				name:  "Customer Forbidden",
+				query: "page_id=1&page_size=5",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, account.Owner, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ListAccountStatusChanges(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				},
+			},
+
+			{
+				name:  "Account Not Found",
+				query: "page_id=1&page_size=5",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(db.Account{}, sql.ErrNoRows)
+					store.EXPECT().
+						ListAccountStatusChanges(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name:  "Invalid Page Size",
+				query: "page_id=1&page_size=50",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ListAccountStatusChanges(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				// create mock store
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				// start test server and send request
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				url := fmt.Sprintf("/accounts/%d/status_changes?%s", account.ID, tc.query)
+				request, err := http.NewRequest(http.MethodGet, url, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+
+				// call the server
+				server.router.ServeHTTP(recorder, request)
+				// check the response
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
@@ -8,6 +8,7 @@ import (
 	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
 	"github.com/Petatron/bank-simulator-backend/token"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
@@ -33,7 +34,17 @@ func addAuthorizations(
 	authorizationType, username string,
 	duration time.Duration,
 ) {
-	resultToken, err := tokenMaker.CreateToken(username, duration)
+	addAuthorizationsWithRole(request, tokenMaker, authorizationType, username, model.RoleCustomer, duration)
+}
+
+func addAuthorizationsWithRole(
+	request *http.Request,
+	tokenMaker token.Maker,
+	authorizationType, username string,
+	role model.Role,
+	duration time.Duration,
+) {
+	resultToken, _, err := tokenMaker.CreateToken(username, role, token.TokenTypeAccess, duration)
 	if err != nil {
 		panic(err)
 	}
@@ -64,6 +75,10 @@ var _ = Describe("API tests", func() {
 						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
 						Times(1).
 						Return(account, nil)
+					store.EXPECT().
+						SumActiveHolds(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(int64(0), nil)
 				},
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
 					requireBodyMatchAccount(recorder.Body, account)
@@ -88,6 +103,97 @@ var _ = Describe("API tests", func() {
 				},
 			},
 
+			{
+				name:      "Teller Views Any Account",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "teller", model.RoleTeller, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						SumActiveHolds(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(int64(0), nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					requireBodyMatchAccount(recorder.Body, account)
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:      "Admin Views Any Account",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						SumActiveHolds(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(int64(0), nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					requireBodyMatchAccount(recorder.Body, account)
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:      "Available Balance With Holds",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						SumActiveHolds(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(int64(10), nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					var gotAccount accountResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &gotAccount)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(gotAccount.Balance).To(Equal(account.Balance))
+					Expect(gotAccount.AvailableBalance).To(Equal(account.Balance - 10))
+				},
+			},
+
+			{
+				name:      "Holds Internal Error",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						SumActiveHolds(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(int64(0), sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+
 			{
 				name:      "No Authorization",
 				accountID: account.ID,
@@ -189,10 +295,10 @@ var _ = Describe("API tests", func() {
 		for i := range accounts {
 			accounts[i] = getRandomAccount(userName)
 		}
-		pageSize := int32(5)
+		total := int64(len(accounts))
 
 		type Query struct {
-			PageID   int32
+			Cursor   string
 			PageSize int32
 		}
 
@@ -206,7 +312,6 @@ var _ = Describe("API tests", func() {
 			{
 				name: "OK",
 				query: Query{
-					PageID:   1,
 					PageSize: 5,
 				},
 				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
@@ -214,37 +319,198 @@ var _ = Describe("API tests", func() {
 				},
 				buildStubs: func(store *mockdb.MockStore) {
 					arg := db.ListAccountsParams{
-						Owner:  userName,
-						Limit:  pageSize,
-						Offset: 0,
+						Owner: userName,
+						Limit: 6,
 					}
 					store.EXPECT().
 						ListAccounts(gomock.Any(), gomock.Eq(arg)).
 						Times(1).
 						Return(accounts, nil)
+					store.EXPECT().
+						CountAccounts(gomock.Any(), gomock.Eq(userName)).
+						Times(1).
+						Return(total, nil)
+					store.EXPECT().
+						SumActiveHoldsByAccounts(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return([]db.SumActiveHoldsByAccountsRow{}, nil)
 				},
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					page := requireBodyMatchAccounts(recorder.Body, accounts)
+					Expect(page.NextCursor).To(BeEmpty())
+					Expect(page.TotalCount).ToNot(BeNil())
+					Expect(*page.TotalCount).To(Equal(total))
+				},
+			},
+
+			{
+				name: "Default Page Size",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.ListAccountsParams{
+						Owner: userName,
+						Limit: defaultPageSize + 1,
+					}
+					store.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(accounts, nil)
+					store.EXPECT().
+						CountAccounts(gomock.Any(), gomock.Eq(userName)).
+						Times(1).
+						Return(total, nil)
+					store.EXPECT().
+						SumActiveHoldsByAccounts(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return([]db.SumActiveHoldsByAccountsRow{}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
 					requireBodyMatchAccounts(recorder.Body, accounts)
+				},
+			},
+
+			{
+				name: "Next Page",
+				query: Query{
+					PageSize: 2,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.ListAccountsParams{
+						Owner: userName,
+						Limit: 3,
+					}
+					store.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(accounts[:3], nil)
+					store.EXPECT().
+						CountAccounts(gomock.Any(), gomock.Eq(userName)).
+						Times(1).
+						Return(total, nil)
+					// The extra row only tells that there is a next page
+					store.EXPECT().
+						SumActiveHoldsByAccounts(gomock.Any(), gomock.Eq([]int64{accounts[0].ID, accounts[1].ID})).
+						Times(1).
+						Return([]db.SumActiveHoldsByAccountsRow{}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
 					Expect(recorder.Code).To(Equal(http.StatusOK))
+					page := requireBodyMatchAccounts(recorder.Body, accounts[:2])
+					Expect(page.NextCursor).To(Equal(encodeCursor(pageCursor{ID: accounts[1].ID})))
+					Expect(*page.TotalCount).To(Equal(total))
 				},
 			},
 
 			{
-				name: "Bad Request",
+				name: "With Cursor",
 				query: Query{
-					PageID:   1,
-					PageSize: 1,
+					Cursor:   encodeCursor(pageCursor{ID: accounts[1].ID}),
+					PageSize: 5,
 				},
 				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
 					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
 				},
 				buildStubs: func(store *mockdb.MockStore) {
 					arg := db.ListAccountsParams{
-						Limit:  1,
-						Offset: 0,
+						Owner:   userName,
+						AfterID: sql.NullInt64{Int64: accounts[1].ID, Valid: true},
+						Limit:   6,
 					}
 					store.EXPECT().
 						ListAccounts(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(accounts[2:], nil)
+					store.EXPECT().
+						CountAccounts(gomock.Any(), gomock.Eq(userName)).
+						Times(1).
+						Return(total, nil)
+					store.EXPECT().
+						SumActiveHoldsByAccounts(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return([]db.SumActiveHoldsByAccountsRow{}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					page := requireBodyMatchAccounts(recorder.Body, accounts[2:])
+					Expect(page.NextCursor).To(BeEmpty())
+				},
+			},
+
+			{
+				name: "Available Balance With Holds",
+				query: Query{
+					PageSize: 5,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					accountIDs := make([]int64, len(accounts))
+					for i := range accounts {
+						accountIDs[i] = accounts[i].ID
+					}
+					store.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(accounts, nil)
+					store.EXPECT().
+						CountAccounts(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(total, nil)
+					store.EXPECT().
+						SumActiveHoldsByAccounts(gomock.Any(), gomock.Eq(accountIDs)).
+						Times(1).
+						Return([]db.SumActiveHoldsByAccountsRow{{AccountID: accounts[1].ID, Total: 25}}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					var page pageResponse[accountResponse]
+					err := json.Unmarshal(recorder.Body.Bytes(), &page)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(page.Items).To(HaveLen(len(accounts)))
+					Expect(page.Items[0].AvailableBalance).To(Equal(accounts[0].Balance))
+					Expect(page.Items[1].AvailableBalance).To(Equal(accounts[1].Balance - 25))
+					Expect(page.Items[1].BalanceDecimal).To(Equal(model.NewMoney(accounts[1].Balance, model.CurrencyType(accounts[1].Currency), 2).String()))
+					Expect(page.Items[1].AvailableBalanceDecimal).To(Equal(model.NewMoney(accounts[1].Balance-25, model.CurrencyType(accounts[1].Currency), 2).String()))
+				},
+			},
+
+			{
+				name: "Bad Request",
+				query: Query{
+					PageSize: 101,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Invalid Cursor",
+				query: Query{
+					Cursor: "not-a-cursor",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Any()).
 						Times(0)
 				},
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -255,20 +521,14 @@ var _ = Describe("API tests", func() {
 			{
 				name: "Internal Error",
 				query: Query{
-					PageID:   1,
 					PageSize: 5,
 				},
 				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
 					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
 				},
 				buildStubs: func(store *mockdb.MockStore) {
-					arg := db.ListAccountsParams{
-						Owner:  userName,
-						Limit:  pageSize,
-						Offset: 0,
-					}
 					store.EXPECT().
-						ListAccounts(gomock.Any(), gomock.Eq(arg)).
+						ListAccounts(gomock.Any(), gomock.Any()).
 						Times(1).
 						Return([]db.Account{}, sql.ErrConnDone)
 				},
@@ -276,6 +536,29 @@ var _ = Describe("API tests", func() {
 					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
 				},
 			},
+
+			{
+				name: "Count Internal Error",
+				query: Query{
+					PageSize: 5,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(accounts, nil)
+					store.EXPECT().
+						CountAccounts(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(int64(0), sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
 		}
 
 		for i := range testCases {
@@ -299,8 +582,12 @@ var _ = Describe("API tests", func() {
 				Expect(err).ShouldNot(HaveOccurred())
 
 				q := request.URL.Query()
-				q.Add("page_id", fmt.Sprintf("%d", tc.query.PageID))
-				q.Add("page_size", fmt.Sprintf("%d", tc.query.PageSize))
+				if tc.query.Cursor != "" {
+					q.Add("cursor", tc.query.Cursor)
+				}
+				if tc.query.PageSize != 0 {
+					q.Add("page_size", fmt.Sprintf("%d", tc.query.PageSize))
+				}
 				request.URL.RawQuery = q.Encode()
 
 				tc.setupAuth(request, server.tokenMaker)
@@ -340,6 +627,7 @@ var _ = Describe("API tests", func() {
 						Owner:    account.Owner,
 						Currency: account.Currency,
 						Balance:  0,
+						Type:     string(model.AccountTypeChecking),
 					}
 					store.EXPECT().
 						CreateAccount(gomock.Any(), gomock.Eq(arg)).
@@ -353,6 +641,55 @@ var _ = Describe("API tests", func() {
 				},
 			},
 
+			{
+				name: "Savings With Nickname",
+				body: gin.H{
+					"currency": account.Currency,
+					"type":     "savings",
+					"nickname": " Rainy day ",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.CreateAccountParams{
+						Owner:    account.Owner,
+						Currency: account.Currency,
+						Balance:  0,
+						Type:     string(model.AccountTypeSavings),
+						Nickname: "Rainy day",
+					}
+					store.EXPECT().
+						CreateAccount(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(account, nil)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Not Valid Type",
+				body: gin.H{
+					"currency": account.Currency,
+					"type":     "brokerage",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						CreateAccount(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
 			{
 				name: "Bad Request",
 				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
@@ -403,6 +740,7 @@ var _ = Describe("API tests", func() {
 						Owner:    account.Owner,
 						Currency: account.Currency,
 						Balance:  0,
+						Type:     string(model.AccountTypeChecking),
 					}
 					store.EXPECT().
 						CreateAccount(gomock.Any(), gomock.Eq(arg)).
@@ -429,6 +767,7 @@ var _ = Describe("API tests", func() {
 						Owner:    account.Owner,
 						Currency: account.Currency,
 						Balance:  0,
+						Type:     string(model.AccountTypeChecking),
 					}
 
 					var pqError *pq.Error
@@ -481,6 +820,286 @@ var _ = Describe("API tests", func() {
 	})
 })
 
+var _ = Describe("API tests", func() {
+	Context("closeAccount API", func() {
+		userName := util.GetRandomOwnerName()
+		account := getRandomAccount(userName)
+		account.Balance = 0
+
+		account.Status = string(model.AccountStatusActive)
+
+		closedAccount := account
+		closedAccount.Status = string(model.AccountStatusClosed)
+		closedAccount.ClosedAt = sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true}
+
+		closeArg := func(changedBy string) db.ChangeAccountStatusTxParams {
+			return db.ChangeAccountStatusTxParams{
+				AccountID: account.ID,
+				Status:    model.AccountStatusClosed,
+				Reason:    "account closed on request",
+				ChangedBy: changedBy,
+			}
+		}
+
+		testCases := []struct {
+			name          string
+			accountID     int64
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:      "OK",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Eq(closeArg(userName))).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{Account: closedAccount}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					requireBodyMatchAccount(recorder.Body, closedAccount)
+				},
+			},
+
+			{
+				name:      "Admin Closes Any Account",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Eq(closeArg("admin"))).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{Account: closedAccount}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:      "Unauthorized User",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:      "Teller Cannot Close Accounts",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "teller", model.RoleTeller, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:      "No Authorization",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Any()).
+						Times(0)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:      "Non Zero Balance",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Eq(closeArg(userName))).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{}, db.ErrAccountNotEmpty)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+				},
+			},
+
+			{
+				name:      "Already Closed",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Eq(closeArg(userName))).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{}, db.ErrAccountClosed)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name:      "Account Frozen",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Eq(closeArg(userName))).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{}, db.ErrAccountFrozen)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+				},
+			},
+
+			{
+				name:      "Not Found",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(db.Account{}, sql.ErrNoRows)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name:      "Internal Error",
+				accountID: account.ID,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						ChangeAccountStatusTx(gomock.Any(), gomock.Eq(closeArg(userName))).
+						Times(1).
+						Return(db.ChangeAccountStatusTxResult{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+
+			{
+				name:      "Invalid ID",
+				accountID: 0,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				// create mock store
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				// start test server and send request
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				url := fmt.Sprintf("/accounts/%d", tc.accountID)
+				request, err := http.NewRequest(http.MethodDelete, url, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+
+				// call the server
+				server.router.ServeHTTP(recorder, request)
+				// check the response
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
+
 func getRandomAccount(owner string) db.Account {
 	return db.Account{
 		ID:       util.GetRandomInt(),
@@ -500,12 +1119,13 @@ func requireBodyMatchAccount(body *bytes.Buffer, account db.Account) {
 	Expect(gotAccount).Should(Equal(account))
 }
 
-func requireBodyMatchAccounts(body *bytes.Buffer, accounts []db.Account) {
+func requireBodyMatchAccounts(body *bytes.Buffer, accounts []db.Account) pageResponse[db.Account] {
 	data, err := io.ReadAll(body)
 	Expect(err).ShouldNot(HaveOccurred())
 
-	var gotAccount []db.Account
-	err = json.Unmarshal(data, &gotAccount)
+	var page pageResponse[db.Account]
+	err = json.Unmarshal(data, &page)
 	Expect(err).ShouldNot(HaveOccurred())
-	Expect(gotAccount).Should(Equal(accounts))
+	Expect(page.Items).Should(Equal(accounts))
+	return page
 }
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	m "github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// batchTransferLegRequest defines a leg of the batchTransfer API request, Amount is in the currency of the batch
+type batchTransferLegRequest struct {
+	ToAccountID int64       `json:"to_account_id" binding:"required,min=1"`
+	Amount      amountValue `json:"amount"`
+}
+
+// batchTransferRequest defines the body for batchTransfer API request.
+// Every leg is paid from the same account, Currency is its currency and amounts are converted like in createTransfer.
+// A batch holds at most 500 legs.
+type batchTransferRequest struct {
+	FromAccountID int64                     `json:"from_account_id" binding:"required,min=1"`
+	Currency      m.CurrencyType            `json:"currency" binding:"required,currency"`
+	Legs          []batchTransferLegRequest `json:"legs" binding:"required,min=1,max=500,dive"`
+}
+
+// batchTransferResponse is the result of each leg of a batch in order, with the amounts as decimal strings
+type batchTransferResponse struct {
+	Legs []transferResponse `json:"legs"`
+}
+
+// batchTransferErrorResponse is the error response of a failed batch, Leg is the index of the leg that failed
+type batchTransferErrorResponse struct {
+	Error string `json:"error"`
+	Leg   int    `json:"leg"`
+}
+
+// batchTransfer implements the API that pays many accounts from one account atomically, e.g. a payroll.
+// Either every leg is transferred or none is, and the response holds the result of each leg in order.
+func (server *Server) batchTransfer(ctx *gin.Context) {
+	var req batchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := server.checkCurrency(req.Currency); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amounts := make([]int64, len(req.Legs))
+	for i, legReq := range req.Legs {
+		amount, err := server.positiveAmount(legReq.Amount, req.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, batchTransferErrorResponse{Error: err.Error(), Leg: i})
+			return
+		}
+		amounts[i] = amount
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	// batchTransfer API rule: A logged-in user can only pay from the accounts they own
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authorizeAccountOwner(authPayload, fromAccount); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// Payrolls often pay the same account more than once, so each destination is only looked up once
+	currencies := make(map[int64]m.CurrencyType)
+	arg := db.BatchTransferTxParams{Legs: make([]db.BatchTransferLeg, len(req.Legs))}
+	for i, legReq := range req.Legs {
+		currency, ok := currencies[legReq.ToAccountID]
+		if !ok {
+			toAccount, valid := server.existingAccount(ctx, legReq.ToAccountID)
+			if !valid {
+				return
+			}
+			currency = m.CurrencyType(toAccount.Currency)
+			currencies[legReq.ToAccountID] = currency
+		}
+
+		leg := db.BatchTransferLeg{
+			FromAccountID: req.FromAccountID,
+			ToAccountID:   legReq.ToAccountID,
+			Amount:        amounts[i],
+			ToAmount:      amounts[i],
+			ExchangeRate:  "1",
+		}
+		if currency != req.Currency {
+			fxArg, ok := server.convertTransfer(ctx, db.TransferTxParams{Amount: amounts[i]}, req.Currency, currency)
+			if !ok {
+				return
+			}
+			leg.ToAmount = fxArg.ToAmount
+			leg.ExchangeRate = fxArg.ExchangeRate
+		}
+		arg.Legs[i] = leg
+	}
+
+	result, err := server.store.BatchTransferTx(ctx, arg)
+	if err != nil {
+		var legErr *db.BatchLegError
+		if errors.As(err, &legErr) &&
+			(errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountClosed) || errors.Is(err, db.ErrAccountFrozen)) {
+			ctx.JSON(http.StatusUnprocessableEntity, batchTransferErrorResponse{Error: err.Error(), Leg: legErr.Leg})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := batchTransferResponse{Legs: make([]transferResponse, len(result.Legs))}
+	for i, leg := range result.Legs {
+		rsp.Legs[i] = server.newTransferResponse(leg, string(req.Currency), string(currencies[req.Legs[i].ToAccountID]))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("batchTransfer API", func() {
+		fromUserName := util.GetRandomOwnerName()
+		fromAccount := getRandomAccount(fromUserName)
+		fromAccount.Currency = "USD"
+		toAccount1 := getRandomAccount(util.GetRandomOwnerName())
+		toAccount1.Currency = "USD"
+		toAccount2 := getRandomAccount(util.GetRandomOwnerName())
+		toAccount2.Currency = "EUR"
+
+		body := gin.H{
+			"from_account_id": fromAccount.ID,
+			"currency":        "USD",
+			"legs": []gin.H{
+				{"to_account_id": toAccount1.ID, "amount": 100},
+				{"to_account_id": toAccount2.ID, "amount": 100},
+				{"to_account_id": toAccount1.ID, "amount": 50},
+			},
+		}
+
+		// stubAccounts expects the lookups of the source account and of each destination once
+		stubAccounts := func(store *mockdb.MockStore) {
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+				Times(1).
+				Return(fromAccount, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(toAccount1.ID)).
+				Times(1).
+				Return(toAccount1, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(toAccount2.ID)).
+				Times(1).
+				Return(toAccount2, nil)
+		}
+
+		testCases := []struct {
+			name          string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name: "OK",
+				body: body,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					stubAccounts(store)
+					arg := db.BatchTransferTxParams{Legs: []db.BatchTransferLeg{
+						{FromAccountID: fromAccount.ID, ToAccountID: toAccount1.ID, Amount: 100, ToAmount: 100, ExchangeRate: "1"},
+						{FromAccountID: fromAccount.ID, ToAccountID: toAccount2.ID, Amount: 100, ToAmount: 92, ExchangeRate: "0.92"},
+						{FromAccountID: fromAccount.ID, ToAccountID: toAccount1.ID, Amount: 50, ToAmount: 50, ExchangeRate: "1"},
+					}}
+					store.EXPECT().
+						BatchTransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(db.BatchTransferTxResult{Legs: []db.TransferTxResult{
+							{Transfer: db.Transfer{Amount: 100, ToAmount: 100}},
+							{Transfer: db.Transfer{Amount: 100, ToAmount: 92}},
+							{Transfer: db.Transfer{Amount: 50, ToAmount: 50}},
+						}}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp batchTransferResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.Legs).To(HaveLen(3))
+					Expect(rsp.Legs[1].AmountDecimal).To(Equal("1.00"))
+					Expect(rsp.Legs[1].ToAmountDecimal).To(Equal("0.92"))
+					Expect(rsp.Legs[2].AmountDecimal).To(Equal("0.50"))
+				},
+			},
+
+			{
+				name: "No Legs",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"currency":        "USD",
+					"legs":            []gin.H{},
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						BatchTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Invalid Leg Amount",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"currency":        "USD",
+					"legs":            []gin.H{{"to_account_id": toAccount1.ID, "amount": -1}},
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						BatchTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Unauthorized User",
+				body: body,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						BatchTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name: "Destination Not Found",
+				body: body,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount1.ID)).
+						Times(1).
+						Return(db.Account{}, sql.ErrNoRows)
+					store.EXPECT().
+						BatchTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name: "Insufficient Funds",
+				body: body,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					stubAccounts(store)
+					store.EXPECT().
+						BatchTransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.BatchTransferTxResult{}, &db.BatchLegError{Leg: 2, Err: db.ErrInsufficientFunds})
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+
+					var response batchTransferErrorResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &response)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(response.Leg).To(Equal(2))
+				},
+			},
+
+			{
+				name: "Internal Server Error",
+				body: body,
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					stubAccounts(store)
+					store.EXPECT().
+						BatchTransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.BatchTransferTxResult{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				request, err := http.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
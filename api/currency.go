@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"github.com/Petatron/bank-simulator-backend/currency"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// listCurrencies implements the API that returns the currency registry, disabled currencies included
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, server.currencies.List())
+}
+
+// putCurrencyURI defines the URI parameters of the putCurrency API
+type putCurrencyURI struct {
+	Code model.CurrencyType `uri:"code" binding:"required,len=3,uppercase"`
+}
+
+// putCurrencyRequest defines the body for putCurrency API request.
+// The exponent is required so that a typo cannot silently register a currency with the wrong minor unit.
+type putCurrencyRequest struct {
+	Exponent *int32 `json:"exponent" binding:"required,min=0,max=4"`
+	Symbol   string `json:"symbol" binding:"max=8"`
+	Enabled  *bool  `json:"enabled" binding:"required"`
+}
+
+// putCurrency implements the admin API that adds a currency to the registry, or updates its symbol and enabled flag.
+// Disabling a currency keeps the existing accounts in it but rejects it in new requests.
+func (server *Server) putCurrency(ctx *gin.Context) {
+	var uri putCurrencyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req putCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.currencies.Put(ctx, currency.Currency{
+		Code:     uri.Code,
+		Exponent: *req.Exponent,
+		Symbol:   req.Symbol,
+		Enabled:  *req.Enabled,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, currency.ErrInvalidCurrency):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, currency.ErrExponentChange):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
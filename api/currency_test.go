@@ -0,0 +1,218 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Petatron/bank-simulator-backend/currency"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("listCurrencies API", func() {
+		It("Test list default currencies", func() {
+			server := newTestServer(nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/currencies", nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			server.router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var currencies []currency.Currency
+			err = json.Unmarshal(recorder.Body.Bytes(), &currencies)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(currencies).To(HaveLen(len(currency.DefaultCurrencies)))
+			Expect(currencies[0].Code).To(Equal(model.CAD))
+		})
+	})
+
+	Context("putCurrency API", func() {
+		testCases := []struct {
+			name          string
+			code          string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			checkResponse func(recorder *httptest.ResponseRecorder, server *Server)
+		}{
+			{
+				name: "Add Currency",
+				code: "JPY",
+				body: gin.H{"exponent": 0, "symbol": "¥", "enabled": true},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var got currency.Currency
+					err := json.Unmarshal(recorder.Body.Bytes(), &got)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(got).To(Equal(currency.Currency{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true}))
+					Expect(currency.IsSupported(server.currencies, "JPY")).To(BeTrue())
+				},
+			},
+
+			{
+				name: "Disable Currency",
+				code: "CAD",
+				body: gin.H{"exponent": 2, "symbol": "CA$", "enabled": false},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					Expect(currency.IsSupported(server.currencies, model.CAD)).To(BeFalse())
+				},
+			},
+
+			{
+				name: "Exponent Change",
+				code: "USD",
+				body: gin.H{"exponent": 3, "symbol": "$", "enabled": true},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name: "Invalid Code",
+				code: "usd",
+				body: gin.H{"exponent": 2, "enabled": true},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Missing Exponent",
+				code: "JPY",
+				body: gin.H{"enabled": true},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Not Admin",
+				code: "JPY",
+				body: gin.H{"exponent": 0, "enabled": true},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server) {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+					Expect(currency.IsSupported(server.currencies, "JPY")).To(BeFalse())
+				},
+			},
+
+			{
+				name: "No Authorization",
+				code: "JPY",
+				body: gin.H{"exponent": 0, "enabled": true},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				server := newTestServer(nil)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				url := fmt.Sprintf("/currencies/%s", tc.code)
+				request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder, server)
+			})
+		}
+
+		It("Test disabled currency is rejected in new requests", func() {
+			controller := gomock.NewController(GinkgoT())
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			store.EXPECT().
+				CreateAccount(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			server := newTestServer(store)
+			_, err := server.currencies.Put(context.Background(), currency.Currency{Code: model.CAD, Exponent: 2})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			body, err := json.Marshal(gin.H{"currency": model.CAD})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(body))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			addAuthorizations(request, server.tokenMaker, authorizationTypeBearer, "user", time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("Test each server validates against its own registry", func() {
+			controller := gomock.NewController(GinkgoT())
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			store.EXPECT().
+				CreateAccount(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(db.Account{Currency: "JPY"}, nil)
+
+			server := newTestServer(store)
+			_, err := server.currencies.Put(context.Background(), currency.Currency{Code: "JPY", Exponent: 0, Enabled: true})
+			Expect(err).ShouldNot(HaveOccurred())
+			// A server created later does not replace the registry the first one validates against
+			other := newTestServer(store)
+
+			send := func(server *Server) int {
+				body, err := json.Marshal(gin.H{"currency": "JPY"})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				addAuthorizations(request, server.tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				server.router.ServeHTTP(recorder, request)
+				return recorder.Code
+			}
+			Expect(send(other)).To(Equal(http.StatusBadRequest))
+			Expect(send(server)).To(Equal(http.StatusOK))
+		})
+	})
+})
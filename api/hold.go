@@ -0,0 +1,235 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	m "github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultHoldDuration is how long a hold reserves funds when the request does not set an expiry
+const defaultHoldDuration = 7 * 24 * time.Hour
+
+// placeHoldRequest defines the body for placeHold API request.
+// Amount is a decimal string in the currency or a whole number of its minor units.
+// ExpiresAt is optional, the hold is released automatically once it passes.
+type placeHoldRequest struct {
+	AccountID   int64          `json:"account_id" binding:"required,min=1"`
+	ToAccountID int64          `json:"to_account_id" binding:"required,min=1"`
+	Amount      amountValue    `json:"amount"`
+	Currency    m.CurrencyType `json:"currency" binding:"required,currency"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+}
+
+// placeHold implements the API that reserves funds of an account for a later capture
+func (server *Server) placeHold(ctx *gin.Context) {
+	var req placeHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := server.checkCurrency(req.Currency); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, err := server.positiveAmount(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	expiresAt := req.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(defaultHoldDuration)
+	}
+	if !expiresAt.After(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "hold must expire in the future"})
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.AccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	// placeHold API rule: A logged-in user can only place a hold on the accounts they own
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authorizeAccountOwner(authPayload, fromAccount); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// A hold is captured at the held amount, so both accounts must use the same currency
+	if _, valid := server.validAccount(ctx, req.ToAccountID, req.Currency); !valid {
+		return
+	}
+
+	hold, err := server.store.PlaceHold(ctx, db.PlaceHoldParams{
+		AccountID:   req.AccountID,
+		ToAccountID: req.ToAccountID,
+		Amount:      amount,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		respondHoldError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newHoldResponse(hold, string(req.Currency)))
+}
+
+// holdResponse is a hold with its amount as a decimal string in the major unit of the currency
+type holdResponse struct {
+	db.Hold
+	AmountDecimal string `json:"amount_decimal,omitempty"`
+}
+
+// newHoldResponse creates the response of a hold between accounts in the given currency
+func (server *Server) newHoldResponse(hold db.Hold, currency string) holdResponse {
+	return holdResponse{
+		Hold:          hold,
+		AmountDecimal: server.formatAmount(hold.Amount, currency),
+	}
+}
+
+// holdURI defines the URI parameters of the hold APIs
+type holdURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// captureHoldRequest defines the body for captureHold API request.
+// Amount is optional, the full held amount is captured if it is not set.
+// It is a decimal string in the currency of the hold or a whole number of its minor units.
+type captureHoldRequest struct {
+	Amount amountValue `json:"amount"`
+}
+
+// captureHoldResponse is the result of a capture with its amounts as decimal strings
+type captureHoldResponse struct {
+	Hold     holdResponse     `json:"hold"`
+	Transfer transferResponse `json:"transfer"`
+}
+
+// captureHold implements the API that moves the held funds to the destination account of the hold
+func (server *Server) captureHold(ctx *gin.Context) {
+	var uri holdURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The body is optional, an empty body captures the full held amount
+	var req captureHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// captureHold API rule: Only the owner of the destination account can capture a hold
+	_, account, valid := server.authorizedHold(ctx, uri.ID, false)
+	if !valid {
+		return
+	}
+
+	// Both accounts of a hold use the same currency
+	amount, err := server.optionalAmount(req.Amount, m.CurrencyType(account.Currency))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CaptureHold(ctx, db.CaptureHoldParams{
+		HoldID: uri.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		respondHoldError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, captureHoldResponse{
+		Hold:     server.newHoldResponse(result.Hold, account.Currency),
+		Transfer: server.newTransferResponse(result.Transfer, account.Currency, account.Currency),
+	})
+}
+
+// releaseHold implements the API that releases a hold without moving any funds
+func (server *Server) releaseHold(ctx *gin.Context) {
+	var uri holdURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// releaseHold API rule: The owner of either account of the hold can release it
+	_, account, valid := server.authorizedHold(ctx, uri.ID, true)
+	if !valid {
+		return
+	}
+
+	hold, err := server.store.ReleaseHold(ctx, uri.ID)
+	if err != nil {
+		respondHoldError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newHoldResponse(hold, account.Currency))
+}
+
+// authorizedHold fetches the given hold and checks that the logged-in user owns its destination account,
+// or its source account if allowPayer is set, and returns the hold with the account of the user.
+// It responds with an error otherwise.
+func (server *Server) authorizedHold(ctx *gin.Context, holdID int64, allowPayer bool) (db.Hold, db.Account, bool) {
+	hold, err := server.store.GetHold(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return hold, db.Account{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return hold, db.Account{}, false
+	}
+
+	accountIDs := []int64{hold.ToAccountID}
+	if allowPayer {
+		accountIDs = append(accountIDs, hold.AccountID)
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, accountID := range accountIDs {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return hold, account, false
+		}
+		if authorizeAccountOwner(authPayload, account) == nil {
+			return hold, account, true
+		}
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("%w: hold %d", errAccountNotOwned, hold.ID)))
+	return hold, db.Account{}, false
+}
+
+// respondHoldError responds with the status code matching an error of the hold transactions
+func respondHoldError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, db.ErrInvalidCaptureAmount):
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	case errors.Is(err, db.ErrHoldNotPending), errors.Is(err, db.ErrHoldExpired):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrAccountClosed), errors.Is(err, db.ErrAccountFrozen):
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
@@ -0,0 +1,653 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("placeHold API", func() {
+		payerName := util.GetRandomOwnerName()
+		merchantName := util.GetRandomOwnerName()
+		fromAccount := getRandomAccount(payerName)
+		fromAccount.Currency = "USD"
+		toAccount := getRandomAccount(merchantName)
+		toAccount.Currency = "USD"
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+		testCases := []struct {
+			name          string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name: "OK",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        10,
+					"currency":      "USD",
+					"expires_at":    expiresAt,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					arg := db.PlaceHoldParams{
+						AccountID:   fromAccount.ID,
+						ToAccountID: toAccount.ID,
+						Amount:      10,
+						ExpiresAt:   expiresAt,
+					}
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(db.Hold{ID: 1, AccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 10}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp holdResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.Amount).To(Equal(int64(10)))
+					Expect(rsp.AmountDecimal).To(Equal("0.10"))
+				},
+			},
+
+			{
+				name: "Default Expiry",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        10,
+					"currency":      "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Any()).
+						Times(1).
+						DoAndReturn(func(_ interface{}, arg db.PlaceHoldParams) (db.Hold, error) {
+							Expect(arg.ExpiresAt).To(BeTemporally("~", time.Now().Add(defaultHoldDuration), time.Minute))
+							return db.Hold{}, nil
+						})
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Expiry In The Past",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        10,
+					"currency":      "USD",
+					"expires_at":    time.Now().Add(-time.Hour),
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Negative Amount",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        -10,
+					"currency":      "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Unauthorized User",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        10,
+					"currency":      "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name: "Currency Mismatch",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        10,
+					"currency":      "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					euroAccount := toAccount
+					euroAccount.Currency = "EUR"
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(euroAccount, nil)
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Insufficient Funds",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        10,
+					"currency":      "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.Hold{}, db.ErrInsufficientFunds)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+				},
+			},
+
+			{
+				name: "No Authorization",
+				body: gin.H{
+					"account_id":    fromAccount.ID,
+					"to_account_id": toAccount.ID,
+					"amount":        10,
+					"currency":      "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						PlaceHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				request, err := http.NewRequest(http.MethodPost, "/holds", bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+
+	Context("captureHold and releaseHold API", func() {
+		payerName := util.GetRandomOwnerName()
+		merchantName := util.GetRandomOwnerName()
+		fromAccount := getRandomAccount(payerName)
+		fromAccount.Currency = "USD"
+		toAccount := getRandomAccount(merchantName)
+		toAccount.Currency = "USD"
+		hold := db.Hold{
+			ID:          util.GetRandomInt(),
+			AccountID:   fromAccount.ID,
+			ToAccountID: toAccount.ID,
+			Amount:      100,
+			Status:      db.HoldStatusPending,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+
+		testCases := []struct {
+			name          string
+			action        string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:   "Capture Full Amount",
+				action: "capture",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Eq(db.CaptureHoldParams{HoldID: hold.ID})).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:   "Capture Partial Amount",
+				action: "capture",
+				body:   gin.H{"amount": 40},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Eq(db.CaptureHoldParams{HoldID: hold.ID, Amount: 40})).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:   "Capture Decimal Amount",
+				action: "capture",
+				body:   gin.H{"amount": "0.40"},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					captured := hold
+					captured.Status = db.HoldStatusCaptured
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Eq(db.CaptureHoldParams{HoldID: hold.ID, Amount: 40})).
+						Times(1).
+						Return(db.CaptureHoldResult{
+							Hold:     captured,
+							Transfer: db.TransferTxResult{Transfer: db.Transfer{Amount: 40, ToAmount: 40}},
+						}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp captureHoldResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.Hold.AmountDecimal).To(Equal("1.00"))
+					Expect(rsp.Transfer.AmountDecimal).To(Equal("0.40"))
+					Expect(rsp.Transfer.ToAmountDecimal).To(Equal("0.40"))
+				},
+			},
+
+			{
+				name:   "Capture Decimal Amount Too Precise",
+				action: "capture",
+				body:   gin.H{"amount": "0.405"},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:   "Payer Cannot Capture",
+				action: "capture",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:   "Capture Expired Hold",
+				action: "capture",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.CaptureHoldResult{}, db.ErrHoldExpired)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name:   "Capture More Than Held",
+				action: "capture",
+				body:   gin.H{"amount": 1000},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.CaptureHoldResult{}, db.ErrInvalidCaptureAmount)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:   "Hold Not Found",
+				action: "capture",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(db.Hold{}, sql.ErrNoRows)
+					store.EXPECT().
+						CaptureHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name:   "Payer Releases Hold",
+				action: "release",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, payerName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						ReleaseHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:   "Merchant Releases Hold",
+				action: "release",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						ReleaseHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:   "Other User Cannot Release",
+				action: "release",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						ReleaseHold(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:   "Release Captured Hold",
+				action: "release",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, merchantName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(hold, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						ReleaseHold(gomock.Any(), gomock.Eq(hold.ID)).
+						Times(1).
+						Return(db.Hold{}, db.ErrHoldNotPending)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				url := fmt.Sprintf("/holds/%d/%s", hold.ID, tc.action)
+				request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
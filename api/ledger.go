@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"time"
+)
+
+// verifyLedgerRequest defines the body for verifyLedger API request.
+// The body is optional, the ledger is verified from the latest checkpoint unless Full is set.
+type verifyLedgerRequest struct {
+	Full bool `json:"full"`
+}
+
+// verifyLedger implements the admin API that checks the balances and transfers against their entries.
+// Discrepancies are reported in the response, the request itself succeeds whether the ledger balances or not.
+func (server *Server) verifyLedger(ctx *gin.Context) {
+	var req verifyLedgerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	report, err := server.store.VerifyLedger(ctx, db.VerifyLedgerParams{
+		Full: req.Full,
+		Now:  time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("verifyLedger API", func() {
+		fullRun := func(full bool) gomock.Matcher {
+			return gomock.Cond(func(x any) bool {
+				arg, ok := x.(db.VerifyLedgerParams)
+				return ok && arg.Full == full && !arg.Now.IsZero()
+			})
+		}
+
+		testCases := []struct {
+			name          string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name: "Incremental",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					report := db.LedgerReport{
+						Checkpoint: db.LedgerCheckpoint{ID: 2, AccountDiscrepancies: 1},
+						Accounts:   []db.ListAccountBalanceMismatchesRow{{AccountID: 7, Balance: 100, EntriesTotal: 90}},
+						Transfers:  []db.ListTransferEntryMismatchesRow{},
+					}
+					store.EXPECT().
+						VerifyLedger(gomock.Any(), fullRun(false)).
+						Times(1).
+						Return(report, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var report db.LedgerReport
+					err := json.Unmarshal(recorder.Body.Bytes(), &report)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(report.Checkpoint.ID).To(Equal(int64(2)))
+					Expect(report.Accounts).To(HaveLen(1))
+					Expect(report.Accounts[0].AccountID).To(Equal(int64(7)))
+					Expect(report.Balanced()).To(BeFalse())
+				},
+			},
+
+			{
+				name: "Full",
+				body: gin.H{"full": true},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						VerifyLedger(gomock.Any(), fullRun(true)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Invalid Body",
+				body: gin.H{"full": "yes"},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						VerifyLedger(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Teller Forbidden",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "teller", model.RoleTeller, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						VerifyLedger(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				},
+			},
+
+			{
+				name: "No Authorization",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						VerifyLedger(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name: "Internal Error",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						VerifyLedger(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.LedgerReport{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				request, err := http.NewRequest(http.MethodPost, "/ledger/verify", bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
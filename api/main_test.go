@@ -11,8 +11,9 @@ import (
 
 func newTestServer(store db.Store) *Server {
 	config := util.Config{
-		TokenSymmetricKey: util.GetRandomStringWithLength(32),
-		AccessToken:       time.Minute,
+		TokenSymmetricKey:    util.GetRandomStringWithLength(32),
+		AccessToken:          time.Minute,
+		RefreshTokenDuration: time.Hour,
 	}
 	server, err := NewServer(config, store)
 	if err != nil {
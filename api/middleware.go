@@ -14,13 +14,27 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// Machine-readable error codes returned by authMiddleware
+const (
+	authErrorMissingHeader   = "missing_authorization_header"
+	authErrorInvalidHeader   = "invalid_authorization_header"
+	authErrorUnsupportedType = "unsupported_authorization_type"
+	authErrorInvalidToken    = "invalid_token"
+	authErrorExpiredToken    = "expired_token"
+	authErrorNotYetValid     = "token_not_yet_valid"
+	authErrorRevokedToken    = "revoked_token"
+	authErrorWrongTokenType  = "wrong_token_type"
+)
+
+// authMiddleware verifies the bearer token of a request and rejects tokens that have been revoked.
+// Only access tokens are accepted, a refresh token can only be exchanged for a new access token.
+func authMiddleware(tokenMaker token.Maker, revocations token.RevocationStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
 		if len(authorizationHeader) == 0 {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, authErrorResponse(authErrorMissingHeader, err))
 			return
 		}
 
@@ -28,7 +42,7 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 
 		if len(files) != 2 {
 			err := errors.New("invalid authorization header format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, authErrorResponse(authErrorInvalidHeader, err))
 			return
 		}
 
@@ -36,14 +50,27 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 
 		if authorizationType != authorizationTypeBearer {
 			err := errors.New("authorization type is not supported")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, authErrorResponse(authErrorUnsupportedType, err))
 			return
 		}
 
 		accessToken := files[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
+		if err == nil {
+			err = payload.CheckType(token.TokenTypeAccess)
+		}
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, authErrorResponse(tokenErrorCode(err), err))
+			return
+		}
+
+		revoked, err := revocations.IsRevoked(ctx, payload.ID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if revoked {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, authErrorResponse(authErrorRevokedToken, token.ErrRevoked))
 			return
 		}
 
@@ -52,3 +79,23 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	}
 
 }
+
+// tokenErrorCode maps an error returned by a token.Maker to its machine-readable code
+func tokenErrorCode(err error) string {
+	switch {
+	case errors.Is(err, token.ErrExpiredToken):
+		return authErrorExpiredToken
+	case errors.Is(err, token.ErrNotYetValid):
+		return authErrorNotYetValid
+	case errors.Is(err, token.ErrRevoked):
+		return authErrorRevokedToken
+	case errors.Is(err, token.ErrWrongTokenType):
+		return authErrorWrongTokenType
+	}
+	return authErrorInvalidToken
+}
+
+// authErrorResponse is errorResponse with a machine-readable code so clients can tell why they were rejected
+func authErrorResponse(code string, err error) gin.H {
+	return gin.H{"error": err.Error(), "code": code}
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/Petatron/bank-simulator-backend/model"
 	"github.com/Petatron/bank-simulator-backend/token"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -17,7 +20,7 @@ func addAuthorization(
 	authorizationType, username string,
 	duration time.Duration,
 ) {
-	resultToken, err := tokenMaker.CreateToken(username, duration)
+	resultToken, _, err := tokenMaker.CreateToken(username, model.RoleCustomer, token.TokenTypeAccess, duration)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -25,6 +28,19 @@ func addAuthorization(
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+func checkAuthErrorCode(t *testing.T, recorder *httptest.ResponseRecorder, code string) {
+	var response struct {
+		Code string `json:"code"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Code != code {
+		t.Errorf("error code should be %q, but got %q", code, response.Code)
+	}
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -51,6 +67,7 @@ func TestAuthMiddleware(t *testing.T) {
 				if recorder.Code != http.StatusUnauthorized {
 					t.Errorf("response code should be %d, but got %d", http.StatusUnauthorized, recorder.Code)
 				}
+				checkAuthErrorCode(t, recorder, authErrorMissingHeader)
 			},
 		},
 
@@ -63,6 +80,7 @@ func TestAuthMiddleware(t *testing.T) {
 				if recorder.Code != http.StatusUnauthorized {
 					t.Errorf("response code should be %d, but got %d", http.StatusUnauthorized, recorder.Code)
 				}
+				checkAuthErrorCode(t, recorder, authErrorUnsupportedType)
 			},
 		},
 
@@ -75,6 +93,7 @@ func TestAuthMiddleware(t *testing.T) {
 				if recorder.Code != http.StatusUnauthorized {
 					t.Errorf("response code should be %d, but got %d", http.StatusUnauthorized, recorder.Code)
 				}
+				checkAuthErrorCode(t, recorder, authErrorInvalidHeader)
 			},
 		},
 
@@ -87,6 +106,37 @@ func TestAuthMiddleware(t *testing.T) {
 				if recorder.Code != http.StatusUnauthorized {
 					t.Errorf("response code should be %d, but got %d", http.StatusUnauthorized, recorder.Code)
 				}
+				checkAuthErrorCode(t, recorder, authErrorExpiredToken)
+			},
+		},
+
+		{
+			name: "MalformedToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, "malformed"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				if recorder.Code != http.StatusUnauthorized {
+					t.Errorf("response code should be %d, but got %d", http.StatusUnauthorized, recorder.Code)
+				}
+				checkAuthErrorCode(t, recorder, authErrorInvalidToken)
+			},
+		},
+
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("test", model.RoleCustomer, token.TokenTypeRefresh, time.Hour)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				if recorder.Code != http.StatusUnauthorized {
+					t.Errorf("response code should be %d, but got %d", http.StatusUnauthorized, recorder.Code)
+				}
+				checkAuthErrorCode(t, recorder, authErrorWrongTokenType)
 			},
 		},
 	}
@@ -99,7 +149,7 @@ func TestAuthMiddleware(t *testing.T) {
 			server := newTestServer(nil)
 			server.router.GET(
 				"/test-auth",
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.revocations),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				})
@@ -115,3 +165,35 @@ func TestAuthMiddleware(t *testing.T) {
 	}
 
 }
+
+func TestAuthMiddlewareRevokedToken(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	server := newTestServer(nil)
+	server.router.GET(
+		"/test-auth",
+		authMiddleware(server.tokenMaker, server.revocations),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		})
+
+	accessToken, payload, err := server.tokenMaker.CreateToken("test", model.RoleCustomer, token.TokenTypeAccess, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = server.revocations.Revoke(context.Background(), payload.ID, payload.ExpiredAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/test-auth", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+	server.router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("response code should be %d, but got %d", http.StatusUnauthorized, recorder.Code)
+	}
+	checkAuthErrorCode(t, recorder, authErrorRevokedToken)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	m "github.com/Petatron/bank-simulator-backend/model"
+	"strconv"
+	"strings"
+)
+
+var (
+	errAmountRequired    = errors.New("amount is required")
+	errAmountNotPositive = errors.New("amount must be greater than zero")
+)
+
+// amountValue is an amount in a request body. It is either a decimal string such as "12.34" in the major unit
+// of the currency, or a whole number of minor units as accepted before decimal strings were supported.
+type amountValue struct {
+	decimal string
+	minor   int64
+	set     bool
+}
+
+// UnmarshalJSON accepts a JSON string holding a decimal amount or a JSON number of minor units
+func (amount *amountValue) UnmarshalJSON(data []byte) error {
+	*amount = amountValue{}
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &amount.decimal); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(data, &amount.minor); err != nil {
+		return fmt.Errorf("amount must be a decimal string or a whole number of minor units: %w", err)
+	}
+	amount.set = true
+	return nil
+}
+
+// UnmarshalParam accepts a query parameter holding a decimal amount with a decimal point, such as "12.00",
+// or a whole number of minor units
+func (amount *amountValue) UnmarshalParam(param string) error {
+	*amount = amountValue{}
+	if strings.Contains(param, ".") {
+		amount.decimal = param
+	} else {
+		minor, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return fmt.Errorf("amount must be a decimal number or a whole number of minor units: %w", err)
+		}
+		amount.minor = minor
+	}
+	amount.set = true
+	return nil
+}
+
+// isDecimal checks if the amount was given as a decimal string, which depends on the currency to be converted
+func (amount amountValue) isDecimal() bool {
+	return amount.decimal != ""
+}
+
+// positiveAmount converts the amount of a request into minor units of the currency and checks it is greater than zero.
+// Decimal strings are parsed with the exponent of the currency in the registry and are never rounded.
+func (server *Server) positiveAmount(amount amountValue, currency m.CurrencyType) (int64, error) {
+	if !amount.set {
+		return 0, errAmountRequired
+	}
+
+	minor := amount.minor
+	if amount.decimal != "" {
+		registered, ok := server.currencies.Lookup(currency)
+		if !ok {
+			return 0, fmt.Errorf("unknown currency %q", currency)
+		}
+		money, err := m.ParseMoney(amount.decimal, currency, registered.Exponent)
+		if err != nil {
+			return 0, err
+		}
+		minor = money.Amount
+	}
+
+	if minor <= 0 {
+		return 0, errAmountNotPositive
+	}
+	return minor, nil
+}
+
+// optionalAmount converts an optional amount of a request like positiveAmount, it returns zero if the amount is not set
+func (server *Server) optionalAmount(amount amountValue, currency m.CurrencyType) (int64, error) {
+	if !amount.set {
+		return 0, nil
+	}
+	return server.positiveAmount(amount, currency)
+}
+
+// formatAmount formats an amount in minor units as a decimal string in the major unit of the currency.
+// It returns an empty string for a currency missing from the registry.
+func (server *Server) formatAmount(amount int64, currency string) string {
+	registered, ok := server.currencies.Lookup(m.CurrencyType(currency))
+	if !ok {
+		return ""
+	}
+	return m.NewMoney(amount, registered.Code, registered.Exponent).String()
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// defaultPageSize is the page size of the keyset paginated list APIs when the request does not set one
+const defaultPageSize = 20
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageRequest defines the query parameters shared by the keyset paginated list APIs, it is embedded in their requests.
+// Cursor is the next_cursor of the previous page and is empty for the first page.
+type pageRequest struct {
+	Cursor   string `form:"cursor"`
+	PageSize int32  `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// pageResponse is a page of a keyset paginated list, NextCursor is empty on the last page.
+// TotalCount is only set by the lists that can count their rows cheaply.
+type pageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	TotalCount *int64 `json:"total_count,omitempty"`
+}
+
+// size returns the requested page size or the default one
+func (req pageRequest) size() int32 {
+	if req.PageSize == 0 {
+		return defaultPageSize
+	}
+	return req.PageSize
+}
+
+// limit returns the number of rows to fetch for the page, the row past the page tells if there is a next one
+func (req pageRequest) limit() int32 {
+	return req.size() + 1
+}
+
+// after returns the key of the last row of the previous page, it is null for the first page
+func (req pageRequest) after() (sql.NullInt64, error) {
+	if req.Cursor == "" {
+		return sql.NullInt64{}, nil
+	}
+	cursor, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+	return sql.NullInt64{Int64: cursor.ID, Valid: true}, nil
+}
+
+// paginate trims the rows fetched with the limit of the request to the page,
+// and returns the cursor of the next page or an empty one if this is the last page
+func paginate[T any](req pageRequest, rows []T, key func(T) int64) ([]T, string) {
+	size := int(req.size())
+	if len(rows) <= size {
+		return rows, ""
+	}
+	rows = rows[:size]
+	return rows, encodeCursor(pageCursor{ID: key(rows[size-1])})
+}
+
+// pageCursor marks the last row of a page of a keyset paginated list, the next page starts right after it.
+// Clients only see it encoded, so the keys can change without breaking them.
+type pageCursor struct {
+	ID int64 `json:"id"`
+}
+
+// encodeCursor returns the opaque form of the cursor that is handed to clients
+func encodeCursor(cursor pageCursor) string {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor handed out by encodeCursor
+func decodeCursor(encoded string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return cursor, errInvalidCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// permission is an action that goes beyond managing the accounts a user owns
+type permission string
+
+const (
+	permissionViewAnyAccount   permission = "account:view_any"
+	permissionCloseAnyAccount  permission = "account:close_any"
+	permissionFreezeAccount    permission = "account:freeze"
+	permissionReverseTransfer  permission = "transfer:reverse"
+	permissionVerifyLedger     permission = "ledger:verify"
+	permissionManageCurrencies permission = "currency:manage"
+	permissionViewMetrics      permission = "metrics:view"
+)
+
+// rolePermissions lists the permissions granted to each role. Every role can manage its own accounts.
+var rolePermissions = map[model.Role][]permission{
+	model.RoleCustomer: {},
+	model.RoleTeller:   {permissionViewAnyAccount},
+	model.RoleAdmin:    {permissionViewAnyAccount, permissionCloseAnyAccount, permissionFreezeAccount, permissionReverseTransfer, permissionVerifyLedger, permissionManageCurrencies, permissionViewMetrics},
+}
+
+var (
+	errAccountNotOwned           = errors.New("account doesn't belong to the authenticated user")
+	errScheduledTransferNotOwned = errors.New("scheduled transfer doesn't belong to the authenticated user")
+	errPermissionDenied          = errors.New("the authenticated user is not allowed to perform this action")
+)
+
+// hasPermission checks if the role carried by the token payload grants the permission
+func hasPermission(payload *token.Payload, perm permission) bool {
+	for _, granted := range rolePermissions[payload.Role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeAccountOwner returns an error unless the authenticated user owns the account.
+// Moving money out of an account is always restricted to its owner.
+func authorizeAccountOwner(payload *token.Payload, account db.Account) error {
+	if account.Owner != payload.Username {
+		return errAccountNotOwned
+	}
+	return nil
+}
+
+// authorizeAccountView returns an error unless the authenticated user owns the account or may view any account
+func authorizeAccountView(payload *token.Payload, account db.Account) error {
+	if hasPermission(payload, permissionViewAnyAccount) {
+		return nil
+	}
+	return authorizeAccountOwner(payload, account)
+}
+
+// authorizeAccountClose returns an error unless the authenticated user owns the account or may close any account
+func authorizeAccountClose(payload *token.Payload, account db.Account) error {
+	if hasPermission(payload, permissionCloseAnyAccount) {
+		return nil
+	}
+	return authorizeAccountOwner(payload, account)
+}
+
+// authorizeScheduledTransferOwner returns an error unless the authenticated user created the scheduled transfer
+func authorizeScheduledTransferOwner(payload *token.Payload, scheduled db.ScheduledTransfer) error {
+	if scheduled.Owner != payload.Username {
+		return errScheduledTransferNotOwned
+	}
+	return nil
+}
+
+// requirePermission creates a middleware that rejects users whose role lacks the permission.
+// It must run after authMiddleware.
+func requirePermission(perm permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !hasPermission(authPayload, perm) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(errPermissionDenied))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequirePermission(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         model.Role
+		permission   permission
+		expectedCode int
+	}{
+		{
+			name:         "CustomerCannotViewAnyAccount",
+			role:         model.RoleCustomer,
+			permission:   permissionViewAnyAccount,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "TellerCanViewAnyAccount",
+			role:         model.RoleTeller,
+			permission:   permissionViewAnyAccount,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "TellerCannotFreezeAccount",
+			role:         model.RoleTeller,
+			permission:   permissionFreezeAccount,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "AdminCanFreezeAccount",
+			role:         model.RoleAdmin,
+			permission:   permissionFreezeAccount,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "AdminCanReverseTransfer",
+			role:         model.RoleAdmin,
+			permission:   permissionReverseTransfer,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "UnknownRole",
+			role:         model.Role("superuser"),
+			permission:   permissionViewAnyAccount,
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server := newTestServer(nil)
+			server.router.GET(
+				"/test-permission",
+				authMiddleware(server.tokenMaker, server.revocations),
+				requirePermission(tc.permission),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				})
+
+			accessToken, _, err := server.tokenMaker.CreateToken("test", tc.role, token.TokenTypeAccess, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			request, err := http.NewRequest(http.MethodGet, "/test-permission", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+			server.router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.expectedCode {
+				t.Errorf("response code should be %d, but got %d", tc.expectedCode, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestMetricsRequirePermission(t *testing.T) {
+	testCases := []struct {
+		name         string
+		setupAuth    func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		expectedCode int
+	}{
+		{
+			name: "Admin",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				accessToken, _, err := tokenMaker.CreateToken("test", model.RoleAdmin, token.TokenTypeAccess, time.Minute)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "Customer",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "test", time.Minute)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server := newTestServer(nil)
+
+			request, err := http.NewRequest(http.MethodGet, "/debug/vars", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.expectedCode {
+				t.Errorf("response code should be %d, but got %d", tc.expectedCode, recorder.Code)
+			}
+		})
+	}
+}
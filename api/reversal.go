@@ -0,0 +1,99 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	m "github.com/Petatron/bank-simulator-backend/model"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+// reverseTransferURI defines the URI parameters of the reverseTransfer API
+type reverseTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// reverseTransferRequest defines the body for reverseTransfer API request.
+// Amount is optional and in the currency of the original source account, the rest of the transfer is refunded if it is not set.
+type reverseTransferRequest struct {
+	Amount amountValue `json:"amount"`
+}
+
+// reverseTransferResponse is the result of a reversal with its amounts as decimal strings in the major unit of each currency
+type reverseTransferResponse struct {
+	OriginalTransfer      transferItemResponse `json:"original_transfer"`
+	Reversal              transferResponse     `json:"reversal"`
+	RefundedAmount        int64                `json:"refunded_amount"`
+	RefundedAmountDecimal string               `json:"refunded_amount_decimal,omitempty"`
+}
+
+// reverseTransfer implements the admin API that refunds a transfer in full or in part
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	var uri reverseTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The body is optional, an empty body refunds the rest of the transfer
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// A decimal amount is in the currency of the original source account, which is only looked up when needed
+	var currency m.CurrencyType
+	if req.Amount.isDecimal() {
+		original, err := server.store.GetTransfer(ctx, uri.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		fromAccount, err := server.store.GetAccount(ctx, original.FromAccountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		currency = m.CurrencyType(fromAccount.Currency)
+	}
+	amount, err := server.optionalAmount(req.Amount, currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: uri.ID,
+		Amount:     amount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrTransferAlreadyReversed):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		case errors.Is(err, db.ErrReversalExceedsTransfer), errors.Is(err, db.ErrInvalidReversal),
+			errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrAccountClosed), errors.Is(err, db.ErrAccountFrozen):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	// The reversal goes back from the original destination account, so the original amounts are in the reverse currencies
+	originalCurrency := result.Reversal.ToAccount.Currency
+	ctx.JSON(http.StatusOK, reverseTransferResponse{
+		OriginalTransfer:      server.newTransferItemResponse(result.OriginalTransfer, originalCurrency, result.Reversal.FromAccount.Currency),
+		Reversal:              server.newTransferResponse(result.Reversal, result.Reversal.FromAccount.Currency, originalCurrency),
+		RefundedAmount:        result.RefundedAmount,
+		RefundedAmountDecimal: server.formatAmount(result.RefundedAmount, originalCurrency),
+	})
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("reverseTransfer API", func() {
+		transferID := util.GetRandomInt()
+
+		testCases := []struct {
+			name          string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name: "Full Reversal",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.ReverseTransferTxParams{TransferID: transferID}
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(db.ReverseTransferTxResult{
+							OriginalTransfer: db.Transfer{ID: transferID, Amount: 100, ToAmount: 92},
+							Reversal: db.TransferTxResult{
+								Transfer:    db.Transfer{Amount: 92, ToAmount: 100},
+								FromAccount: db.Account{Currency: "EUR"},
+								ToAccount:   db.Account{Currency: "USD"},
+							},
+							RefundedAmount: 100,
+						}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp reverseTransferResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.RefundedAmount).To(Equal(int64(100)))
+					Expect(rsp.RefundedAmountDecimal).To(Equal("1.00"))
+					Expect(rsp.OriginalTransfer.AmountDecimal).To(Equal("1.00"))
+					Expect(rsp.OriginalTransfer.ToAmountDecimal).To(Equal("0.92"))
+					Expect(rsp.Reversal.AmountDecimal).To(Equal("0.92"))
+					Expect(rsp.Reversal.ToAmountDecimal).To(Equal("1.00"))
+				},
+			},
+
+			{
+				name: "Partial Refund",
+				body: gin.H{"amount": 40},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.ReverseTransferTxParams{TransferID: transferID, Amount: 40}
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Decimal Partial Refund",
+				body: gin.H{"amount": "0.40"},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetTransfer(gomock.Any(), gomock.Eq(transferID)).
+						Times(1).
+						Return(db.Transfer{ID: transferID, FromAccountID: 1}, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(int64(1))).
+						Times(1).
+						Return(db.Account{ID: 1, Currency: "USD"}, nil)
+					arg := db.ReverseTransferTxParams{TransferID: transferID, Amount: 40}
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Decimal Refund Of Unknown Transfer",
+				body: gin.H{"amount": "0.40"},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetTransfer(gomock.Any(), gomock.Eq(transferID)).
+						Times(1).
+						Return(db.Transfer{}, sql.ErrNoRows)
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name: "Decimal Refund Too Precise",
+				body: gin.H{"amount": "0.405"},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetTransfer(gomock.Any(), gomock.Eq(transferID)).
+						Times(1).
+						Return(db.Transfer{ID: transferID, FromAccountID: 1}, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(int64(1))).
+						Times(1).
+						Return(db.Account{ID: 1, Currency: "USD"}, nil)
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Negative Amount",
+				body: gin.H{"amount": -40},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Customer Forbidden",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "customer", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				},
+			},
+
+			{
+				name: "Teller Forbidden",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "teller", model.RoleTeller, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				},
+			},
+
+			{
+				name: "Not Found",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ReverseTransferTxResult{}, sql.ErrNoRows)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name: "Already Reversed",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ReverseTransferTxResult{}, db.ErrTransferAlreadyReversed)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name: "Refund Exceeds Transfer",
+				body: gin.H{"amount": 1000},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ReverseTransferTxResult{}, db.ErrReversalExceedsTransfer)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+				},
+			},
+
+			{
+				name: "Internal Error",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						ReverseTransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ReverseTransferTxResult{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				url := fmt.Sprintf("/transfers/%d/reverse", transferID)
+				request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
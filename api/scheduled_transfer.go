@@ -0,0 +1,367 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	m "github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// createScheduledTransferRequest defines the body for createScheduledTransfer API request.
+// The first occurrence runs at StartAt, EndAt optionally ends a recurring schedule.
+// Amount is a decimal string in the currency or a whole number of its minor units.
+type createScheduledTransferRequest struct {
+	FromAccountID int64               `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64               `json:"to_account_id" binding:"required,min=1"`
+	Amount        amountValue         `json:"amount"`
+	Currency      m.CurrencyType      `json:"currency" binding:"required,currency"`
+	Frequency     m.ScheduleFrequency `json:"frequency" binding:"required,oneof=once daily weekly monthly"`
+	StartAt       time.Time           `json:"start_at" binding:"required"`
+	EndAt         *time.Time          `json:"end_at"`
+}
+
+// createScheduledTransfer implements the API that schedules a one-off or recurring transfer
+func (server *Server) createScheduledTransfer(ctx *gin.Context) {
+	var req createScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := server.checkCurrency(req.Currency); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, err := server.positiveAmount(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !req.StartAt.After(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "scheduled transfer must start in the future"})
+		return
+	}
+	endAt, valid := scheduleEnd(ctx, req.EndAt, req.StartAt)
+	if !valid {
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	// createScheduledTransfer API rule: A logged-in user can only schedule transfers out of the accounts they own
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authorizeAccountOwner(authPayload, fromAccount); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// The amount is moved as is when the schedule runs, so both accounts must use the same currency
+	if _, valid := server.validAccount(ctx, req.ToAccountID, req.Currency); !valid {
+		return
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		Owner:         authPayload.Username,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        amount,
+		Frequency:     string(req.Frequency),
+		StartAt:       req.StartAt,
+		EndAt:         endAt,
+		NextRunAt:     req.StartAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newScheduledTransferResponse(scheduled, string(req.Currency)))
+}
+
+// scheduledTransferResponse is a scheduled transfer with its amount as a decimal string in the currency of its accounts
+type scheduledTransferResponse struct {
+	db.ScheduledTransfer
+	AmountDecimal string `json:"amount_decimal,omitempty"`
+}
+
+// newScheduledTransferResponse creates the response of a scheduled transfer between accounts in the given currency
+func (server *Server) newScheduledTransferResponse(scheduled db.ScheduledTransfer, currency string) scheduledTransferResponse {
+	return scheduledTransferResponse{
+		ScheduledTransfer: scheduled,
+		AmountDecimal:     server.formatAmount(scheduled.Amount, currency),
+	}
+}
+
+// scheduleCurrency fetches the currency of the source account of a scheduled transfer, which both of its accounts use.
+// It responds with an error if the account cannot be fetched.
+func (server *Server) scheduleCurrency(ctx *gin.Context, scheduled db.ScheduledTransfer) (string, bool) {
+	fromAccount, err := server.store.GetAccount(ctx, scheduled.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return "", false
+	}
+	return fromAccount.Currency, true
+}
+
+// listScheduledTransfersRequest defines the query for listScheduledTransfers API request
+type listScheduledTransfersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listScheduledTransfers implements the API that returns the scheduled transfers of the logged-in user
+func (server *Server) listScheduledTransfers(ctx *gin.Context) {
+	var req listScheduledTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	scheduled, err := server.store.ListScheduledTransfers(ctx, db.ListScheduledTransfersParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Schedules of the same account share its currency, so each account is only fetched once
+	currencies := make(map[int64]string)
+	rsp := make([]scheduledTransferResponse, len(scheduled))
+	for i, item := range scheduled {
+		currency, ok := currencies[item.FromAccountID]
+		if !ok {
+			if currency, ok = server.scheduleCurrency(ctx, item); !ok {
+				return
+			}
+			currencies[item.FromAccountID] = currency
+		}
+		rsp[i] = server.newScheduledTransferResponse(item, currency)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// scheduledTransferURI defines the URI parameters of the scheduled transfer APIs
+type scheduledTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getScheduledTransfer implements the API that returns a scheduled transfer of the logged-in user
+func (server *Server) getScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownedScheduledTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+	currency, valid := server.scheduleCurrency(ctx, scheduled)
+	if !valid {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newScheduledTransferResponse(scheduled, currency))
+}
+
+// updateScheduledTransferRequest defines the body for updateScheduledTransfer API request.
+// A schedule is paused and resumed through its status, an empty EndAt lets it run indefinitely.
+// Amount is a decimal string in the currency of the source account or a whole number of its minor units.
+type updateScheduledTransferRequest struct {
+	Amount amountValue `json:"amount"`
+	Status string      `json:"status" binding:"required,oneof=active paused"`
+	EndAt  *time.Time  `json:"end_at"`
+}
+
+// updateScheduledTransfer implements the API that changes the amount, end or status of a scheduled transfer
+func (server *Server) updateScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownedScheduledTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+	if !scheduleIsOpen(ctx, scheduled) {
+		return
+	}
+	endAt, valid := scheduleEnd(ctx, req.EndAt, scheduled.StartAt)
+	if !valid {
+		return
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, scheduled.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	amount, err := server.positiveAmount(req.Amount, m.CurrencyType(fromAccount.Currency))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, err = server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:     scheduled.ID,
+		Amount: amount,
+		Status: req.Status,
+		EndAt:  endAt,
+	})
+	if err != nil {
+		scheduleUpdateError(ctx, uri.ID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newScheduledTransferResponse(scheduled, fromAccount.Currency))
+}
+
+// cancelScheduledTransfer implements the API that cancels a scheduled transfer.
+// The schedule is kept with a cancelled status so its run history stays intact.
+func (server *Server) cancelScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownedScheduledTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+	if !scheduleIsOpen(ctx, scheduled) {
+		return
+	}
+	currency, valid := server.scheduleCurrency(ctx, scheduled)
+	if !valid {
+		return
+	}
+
+	scheduled, err := server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:     scheduled.ID,
+		Amount: scheduled.Amount,
+		Status: db.ScheduledTransferStatusCancelled,
+		EndAt:  scheduled.EndAt,
+	})
+	if err != nil {
+		scheduleUpdateError(ctx, uri.ID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newScheduledTransferResponse(scheduled, currency))
+}
+
+// listScheduledTransferRunsRequest defines the query for listScheduledTransferRuns API request
+type listScheduledTransferRunsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listScheduledTransferRuns implements the API that returns the run history of a scheduled transfer
+func (server *Server) listScheduledTransferRuns(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listScheduledTransferRunsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := server.ownedScheduledTransfer(ctx, uri.ID); !valid {
+		return
+	}
+
+	runs, err := server.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: uri.ID,
+		Limit:               req.PageSize,
+		Offset:              (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// ownedScheduledTransfer fetches the given scheduled transfer and responds with an error
+// if it cannot be found or does not belong to the logged-in user
+func (server *Server) ownedScheduledTransfer(ctx *gin.Context, id int64) (db.ScheduledTransfer, bool) {
+	scheduled, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return scheduled, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return scheduled, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authorizeScheduledTransferOwner(authPayload, scheduled); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return scheduled, false
+	}
+
+	return scheduled, true
+}
+
+// scheduleIsOpen responds with an error if the scheduled transfer is completed or cancelled
+func scheduleIsOpen(ctx *gin.Context, scheduled db.ScheduledTransfer) bool {
+	switch scheduled.Status {
+	case db.ScheduledTransferStatusCompleted, db.ScheduledTransferStatusCancelled:
+		err := fmt.Errorf("scheduled transfer %d is %s", scheduled.ID, scheduled.Status)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+// scheduleUpdateError responds to a failed update of a scheduled transfer.
+// No row is updated when the schedule completed or was cancelled after it was read.
+func scheduleUpdateError(ctx *gin.Context, id int64, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		err := fmt.Errorf("scheduled transfer %d is no longer active or paused", id)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+}
+
+// scheduleEnd converts the optional end of a schedule and responds with an error if it is before the start
+func scheduleEnd(ctx *gin.Context, endAt *time.Time, startAt time.Time) (sql.NullTime, bool) {
+	if endAt == nil {
+		return sql.NullTime{}, true
+	}
+	if endAt.Before(startAt) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "scheduled transfer cannot end before it starts"})
+		return sql.NullTime{}, false
+	}
+	return sql.NullTime{Time: *endAt, Valid: true}, true
+}
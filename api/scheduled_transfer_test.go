@@ -0,0 +1,669 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("createScheduledTransfer API", func() {
+		userName := util.GetRandomOwnerName()
+		fromAccount := getRandomAccount(userName)
+		fromAccount.Currency = "USD"
+		toAccount := getRandomAccount(util.GetRandomOwnerName())
+		toAccount.Currency = "USD"
+		startAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		endAt := startAt.AddDate(1, 0, 0)
+
+		testCases := []struct {
+			name          string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name: "OK",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          100,
+					"currency":        "USD",
+					"frequency":       "monthly",
+					"start_at":        startAt,
+					"end_at":          endAt,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					arg := db.CreateScheduledTransferParams{
+						Owner:         userName,
+						FromAccountID: fromAccount.ID,
+						ToAccountID:   toAccount.ID,
+						Amount:        100,
+						Frequency:     "monthly",
+						StartAt:       startAt,
+						EndAt:         sql.NullTime{Time: endAt, Valid: true},
+						NextRunAt:     startAt,
+					}
+					store.EXPECT().
+						CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(db.ScheduledTransfer{ID: 1, Owner: userName, Amount: 100}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp scheduledTransferResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.AmountDecimal).To(Equal("1.00"))
+				},
+			},
+
+			{
+				name: "Invalid Frequency",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          100,
+					"currency":        "USD",
+					"frequency":       "hourly",
+					"start_at":        startAt,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Start In The Past",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          100,
+					"currency":        "USD",
+					"frequency":       "once",
+					"start_at":        time.Now().Add(-time.Hour),
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "End Before Start",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          100,
+					"currency":        "USD",
+					"frequency":       "daily",
+					"start_at":        startAt,
+					"end_at":          startAt.Add(-time.Minute),
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Unauthorized User",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          100,
+					"currency":        "USD",
+					"frequency":       "weekly",
+					"start_at":        startAt,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name: "Internal Error",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          100,
+					"currency":        "USD",
+					"frequency":       "weekly",
+					"start_at":        startAt,
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ScheduledTransfer{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				request, err := http.NewRequest(http.MethodPost, "/transfers/scheduled", bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+
+	Context("scheduled transfer management APIs", func() {
+		userName := util.GetRandomOwnerName()
+		fromAccount := getRandomAccount(userName)
+		fromAccount.Currency = "USD"
+		scheduled := db.ScheduledTransfer{
+			ID:            util.GetRandomInt(),
+			Owner:         userName,
+			FromAccountID: fromAccount.ID,
+			Amount:        100,
+			Frequency:     "monthly",
+			StartAt:       time.Now().Add(time.Hour),
+			Status:        db.ScheduledTransferStatusActive,
+		}
+
+		testCases := []struct {
+			name          string
+			method        string
+			url           string
+			body          gin.H
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:   "Get OK",
+				method: http.MethodGet,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp scheduledTransferResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.ID).To(Equal(scheduled.ID))
+					Expect(rsp.AmountDecimal).To(Equal("1.00"))
+				},
+			},
+
+			{
+				name:   "Get Not Found",
+				method: http.MethodGet,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(db.ScheduledTransfer{}, sql.ErrNoRows)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name:   "Get Unauthorized User",
+				method: http.MethodGet,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:   "List OK",
+				method: http.MethodGet,
+				url:    "/transfers/scheduled?page_id=1&page_size=5",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.ListScheduledTransfersParams{
+						Owner:  userName,
+						Limit:  5,
+						Offset: 0,
+					}
+					store.EXPECT().
+						ListScheduledTransfers(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return([]db.ScheduledTransfer{scheduled, scheduled}, nil)
+					// The account shared by both schedules is fetched once
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp []scheduledTransferResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp).To(HaveLen(2))
+					Expect(rsp[1].AmountDecimal).To(Equal("1.00"))
+				},
+			},
+
+			{
+				name:   "Pause OK",
+				method: http.MethodPut,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				body: gin.H{
+					"amount": 200,
+					"status": "paused",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					arg := db.UpdateScheduledTransferParams{
+						ID:     scheduled.ID,
+						Amount: 200,
+						Status: db.ScheduledTransferStatusPaused,
+					}
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:   "Update Decimal Amount",
+				method: http.MethodPut,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				body: gin.H{
+					"amount": "2.50",
+					"status": "active",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					arg := db.UpdateScheduledTransferParams{
+						ID:     scheduled.ID,
+						Amount: 250,
+						Status: db.ScheduledTransferStatusActive,
+					}
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:   "Update Invalid Decimal Amount",
+				method: http.MethodPut,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				body: gin.H{
+					"amount": "2.505",
+					"status": "active",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:   "Update Schedule Completed Concurrently",
+				method: http.MethodPut,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				body: gin.H{
+					"amount": 200,
+					"status": "active",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					// The schedule completed between the read and the update, so no row matches
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ScheduledTransfer{}, sql.ErrNoRows)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name:   "Update Invalid Status",
+				method: http.MethodPut,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				body: gin.H{
+					"amount": 200,
+					"status": "completed",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:   "Update Cancelled Schedule",
+				method: http.MethodPut,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				body: gin.H{
+					"amount": 200,
+					"status": "active",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					cancelled := scheduled
+					cancelled.Status = db.ScheduledTransferStatusCancelled
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(cancelled, nil)
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name:   "Cancel OK",
+				method: http.MethodDelete,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					arg := db.UpdateScheduledTransferParams{
+						ID:     scheduled.ID,
+						Amount: scheduled.Amount,
+						Status: db.ScheduledTransferStatusCancelled,
+					}
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:   "Cancel Unauthorized User",
+				method: http.MethodDelete,
+				url:    fmt.Sprintf("/transfers/scheduled/%d", scheduled.ID),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						UpdateScheduledTransfer(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:   "List Runs OK",
+				method: http.MethodGet,
+				url:    fmt.Sprintf("/transfers/scheduled/%d/runs?page_id=1&page_size=5", scheduled.ID),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					arg := db.ListScheduledTransferRunsParams{
+						ScheduledTransferID: scheduled.ID,
+						Limit:               5,
+						Offset:              0,
+					}
+					store.EXPECT().
+						ListScheduledTransferRuns(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return([]db.ScheduledTransferRun{{ID: 1, Outcome: db.ScheduledTransferRunSkipped}}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					var runs []db.ScheduledTransferRun
+					err := json.Unmarshal(recorder.Body.Bytes(), &runs)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(runs).To(HaveLen(1))
+					Expect(runs[0].Outcome).To(Equal(db.ScheduledTransferRunSkipped))
+				},
+			},
+
+			{
+				name:   "List Runs Unauthorized User",
+				method: http.MethodGet,
+				url:    fmt.Sprintf("/transfers/scheduled/%d/runs?page_id=1&page_size=5", scheduled.ID),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).
+						Times(1).
+						Return(scheduled, nil)
+					store.EXPECT().
+						ListScheduledTransferRuns(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				body, err := json.Marshal(tc.body)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
@@ -1,40 +1,66 @@
 package api
 
 import (
+	"context"
+	"expvar"
 	"fmt"
+	"github.com/Petatron/bank-simulator-backend/currency"
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/exchange"
 	"github.com/Petatron/bank-simulator-backend/token"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"os"
 )
 
 // Server serves HTTP requests for our banking service.
 type Server struct {
-	config     util.Config
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
+	config      util.Config
+	store       db.Store
+	tokenMaker  token.Maker
+	revocations token.RevocationStore
+	fxRates     exchange.FXRateProvider
+	currencies  currency.Registry
+	router      *gin.Engine
 }
 
 // NewServer creates a new HTTP server and set up routing.
 func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	tokenMaker, err := newTokenMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w ", err)
 	}
 
+	fxRates, err := newFXRateProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create exchange rate provider: %w", err)
+	}
+
+	revocations, err := newRevocationStore(config, store)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies, err := newCurrencyRegistry(config, store)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create currency registry: %w", err)
+	}
+
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:      config,
+		store:       store,
+		tokenMaker:  tokenMaker,
+		revocations: revocations,
+		fxRates:     fxRates,
+		currencies:  currencies,
 	}
 	// Set up currency validation
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		err := v.RegisterValidation("currency", validCurrency)
 		if err != nil {
-			return nil, nil
+			return nil, fmt.Errorf("cannot register currency validation: %w", err)
 		}
 	}
 
@@ -43,24 +69,113 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	return server, nil
 }
 
+// newTokenMaker creates the configured token maker, which is PASETO unless jwt is set
+func newTokenMaker(config util.Config) (token.Maker, error) {
+	switch config.TokenType {
+	case "", "paseto":
+		return token.NewPasetoMaker(config.TokenSymmetricKey)
+	case "jwt":
+		if config.TokenAlgorithm == "" || config.TokenAlgorithm == "HS256" {
+			return token.NewJWTMaker(config.TokenSymmetricKey)
+		}
+
+		privateKey, err := os.ReadFile(config.TokenPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read token private key: %w", err)
+		}
+
+		switch config.TokenAlgorithm {
+		case "EdDSA":
+			return token.NewEdDSAJWTMaker(privateKey)
+		case "RS256":
+			return token.NewRS256JWTMaker(privateKey)
+		}
+		return nil, fmt.Errorf("unknown JWT algorithm %q", config.TokenAlgorithm)
+	}
+	return nil, fmt.Errorf("unknown token type %q", config.TokenType)
+}
+
+// newRevocationStore creates the configured token revocation store, which is in memory unless postgres is set
+func newRevocationStore(config util.Config, store db.Store) (token.RevocationStore, error) {
+	switch config.TokenRevocationStore {
+	case "", "memory":
+		return token.NewMemoryRevocationStore(), nil
+	case "postgres":
+		return token.NewPostgresRevocationStore(store), nil
+	}
+	return nil, fmt.Errorf("unknown token revocation store %q", config.TokenRevocationStore)
+}
+
+// newFXRateProvider loads the exchange rates from the configured file, or falls back to the default rate table.
+func newFXRateProvider(config util.Config) (exchange.FXRateProvider, error) {
+	if config.FXRatesFile != "" {
+		return exchange.NewFileRateProvider(config.FXRatesFile)
+	}
+	return exchange.NewStaticRateProvider(exchange.DefaultQuotes)
+}
+
+// newCurrencyRegistry creates the configured currency registry, which is in memory unless postgres is set.
+// The memory registry loads the configured currency file, or falls back to the default currency table.
+func newCurrencyRegistry(config util.Config, store db.Store) (currency.Registry, error) {
+	switch config.CurrencyRegistry {
+	case "", "memory":
+		if config.CurrenciesFile != "" {
+			return currency.NewFileRegistry(config.CurrenciesFile)
+		}
+		return currency.NewMemoryRegistry(currency.DefaultCurrencies)
+	case "postgres":
+		return currency.NewPostgresRegistry(context.Background(), store)
+	}
+	return nil, fmt.Errorf("unknown currency registry %q", config.CurrencyRegistry)
+}
+
 // setupRouter sets up all the routes for the HTTP server.
 func (server *Server) setupRouter() {
 	route := gin.Default()
 
 	route.POST("/users", server.createUser)
 	route.POST("/users/login", server.loginUser)
+	route.POST("/tokens/renew_access", server.renewAccessToken)
+	route.GET("/currencies", server.listCurrencies)
 
-	authRoutes := route.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes := route.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations))
+
+	authRoutes.POST("/users/logout", server.logoutUser)
 
 	authRoutes.POST("/accounts", server.createAccount)
 	authRoutes.GET("/accounts/:id", server.getAccount)
+	authRoutes.GET("/accounts/:id/statement", server.getStatement)
 	authRoutes.GET("/accounts", server.listAccount)
-	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
+	authRoutes.DELETE("/accounts/:id", server.closeAccount)
+	authRoutes.PUT("/accounts/:id/status", requirePermission(permissionFreezeAccount), server.updateAccountStatus)
+	authRoutes.GET("/accounts/:id/status_changes", requirePermission(permissionViewAnyAccount), server.listAccountStatusChanges)
 	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.GET("/transfers", server.listTransfers)
+	authRoutes.POST("/transfers/batch", server.batchTransfer)
+	authRoutes.POST("/transfers/:id/reverse", requirePermission(permissionReverseTransfer), server.reverseTransfer)
+	authRoutes.POST("/transfers/scheduled", server.createScheduledTransfer)
+	authRoutes.GET("/transfers/scheduled", server.listScheduledTransfers)
+	authRoutes.GET("/transfers/scheduled/:id", server.getScheduledTransfer)
+	authRoutes.PUT("/transfers/scheduled/:id", server.updateScheduledTransfer)
+	authRoutes.DELETE("/transfers/scheduled/:id", server.cancelScheduledTransfer)
+	authRoutes.GET("/transfers/scheduled/:id/runs", server.listScheduledTransferRuns)
+	authRoutes.POST("/holds", server.placeHold)
+	authRoutes.POST("/holds/:id/capture", server.captureHold)
+	authRoutes.POST("/holds/:id/release", server.releaseHold)
+	authRoutes.POST("/ledger/verify", requirePermission(permissionVerifyLedger), server.verifyLedger)
+	authRoutes.PUT("/currencies/:code", requirePermission(permissionManageCurrencies), server.putCurrency)
+
+	// Metrics such as the transaction retries of the store, in the expvar JSON format
+	authRoutes.GET("/debug/vars", requirePermission(permissionViewMetrics), gin.WrapH(expvar.Handler()))
 
 	server.router = route
 }
 
+// Currencies returns the currency registry of the server
+func (server *Server) Currencies() currency.Registry {
+	return server.currencies
+}
+
 // Start runs the HTTP server on a specific address.
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	statementFormatJSON = "json"
+	statementFormatCSV  = "csv"
+)
+
+// getStatementURI defines the URI parameters for getStatement API request
+type getStatementURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getStatementQuery defines the query parameters for getStatement API request.
+// From and To are RFC 3339 timestamps, the statement covers [from, to).
+type getStatementQuery struct {
+	From   time.Time `form:"from" binding:"required"`
+	To     time.Time `form:"to" binding:"required,gtfield=From"`
+	Format string    `form:"format" binding:"omitempty,oneof=json csv"`
+	Search string    `form:"search" binding:"max=255"`
+}
+
+// getStatement implements the API that returns the statement of an account for a period
+func (server *Server) getStatement(ctx *gin.Context) {
+	var uri getStatementURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req getStatementQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// getStatement API rule: A logged-in user can only get the statement of an account they own, unless their role may view any account
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authorizeAccountView(authPayload, account); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	statement, err := server.store.AccountStatementTx(ctx, db.AccountStatementTxParams{
+		AccountID: account.ID,
+		From:      req.From,
+		To:        req.To,
+		Search:    req.Search,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := server.newStatementResponse(statement)
+	if req.Format == statementFormatCSV {
+		data, err := statementCSV(rsp)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		filename := fmt.Sprintf("statement-%d.csv", account.ID)
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		ctx.Data(http.StatusOK, "text/csv", data)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// statementResponse is the statement of an account with its amounts and balances also as decimal strings
+// in the major unit of the account currency
+type statementResponse struct {
+	Account               db.Account               `json:"account"`
+	From                  time.Time                `json:"from"`
+	To                    time.Time                `json:"to"`
+	OpeningBalance        int64                    `json:"opening_balance"`
+	OpeningBalanceDecimal string                   `json:"opening_balance_decimal,omitempty"`
+	Entries               []statementEntryResponse `json:"entries"`
+	ClosingBalance        int64                    `json:"closing_balance"`
+	ClosingBalanceDecimal string                   `json:"closing_balance_decimal,omitempty"`
+}
+
+// statementEntryResponse is an entry of a statement with its amount and the running balance as decimal strings
+type statementEntryResponse struct {
+	db.StatementEntry
+	AmountDecimal  string `json:"amount_decimal,omitempty"`
+	BalanceDecimal string `json:"balance_decimal,omitempty"`
+}
+
+// newStatementResponse creates the response of a statement in the currency of its account
+func (server *Server) newStatementResponse(statement db.AccountStatementTxResult) statementResponse {
+	currency := statement.Account.Currency
+	entries := make([]statementEntryResponse, len(statement.Entries))
+	for i, entry := range statement.Entries {
+		entries[i] = statementEntryResponse{
+			StatementEntry: entry,
+			AmountDecimal:  server.formatAmount(entry.Amount, currency),
+			BalanceDecimal: server.formatAmount(entry.Balance, currency),
+		}
+	}
+	return statementResponse{
+		Account:               statement.Account,
+		From:                  statement.From,
+		To:                    statement.To,
+		OpeningBalance:        statement.OpeningBalance,
+		OpeningBalanceDecimal: server.formatAmount(statement.OpeningBalance, currency),
+		Entries:               entries,
+		ClosingBalance:        statement.ClosingBalance,
+		ClosingBalanceDecimal: server.formatAmount(statement.ClosingBalance, currency),
+	}
+}
+
+// statementCSV renders a statement as CSV, with the opening and closing balance as the first and last rows.
+// The decimal columns come last so readers of the earlier columns are not affected.
+func statementCSV(statement statementResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{
+		{"type", "entry_id", "created_at", "amount", "balance", "currency", "description", "external_reference", "amount_decimal", "balance_decimal"},
+		{"opening_balance", "", statement.From.Format(time.RFC3339), "", strconv.FormatInt(statement.OpeningBalance, 10), statement.Account.Currency, "", "", "", statement.OpeningBalanceDecimal},
+	}
+	for _, entry := range statement.Entries {
+		records = append(records, []string{
+			"entry",
+			strconv.FormatInt(entry.ID, 10),
+			entry.CreatedAt.Format(time.RFC3339),
+			strconv.FormatInt(entry.Amount, 10),
+			strconv.FormatInt(entry.Balance, 10),
+			statement.Account.Currency,
+			entry.Description,
+			entry.ExternalReference,
+			entry.AmountDecimal,
+			entry.BalanceDecimal,
+		})
+	}
+	records = append(records, []string{"closing_balance", "", statement.To.Format(time.RFC3339), "", strconv.FormatInt(statement.ClosingBalance, 10), statement.Account.Currency, "", "", "", statement.ClosingBalanceDecimal})
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
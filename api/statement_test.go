@@ -0,0 +1,273 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/token"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("getStatement API", func() {
+		userName := util.GetRandomOwnerName()
+		account := getRandomAccount(userName)
+		account.Currency = "USD"
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+		statement := db.AccountStatementTxResult{
+			Account:        account,
+			From:           from,
+			To:             to,
+			OpeningBalance: 100,
+			Entries: []db.StatementEntry{
+				{Entry: db.Entry{ID: 1, AccountID: account.ID, Amount: -30, CreatedAt: from.Add(time.Hour), Description: "Rent", ExternalReference: "INV-1"}, Balance: 70},
+				{Entry: db.Entry{ID: 2, AccountID: account.ID, Amount: 50, CreatedAt: from.Add(2 * time.Hour)}, Balance: 120},
+			},
+			ClosingBalance: 120,
+		}
+		statementArg := db.AccountStatementTxParams{
+			AccountID: account.ID,
+			From:      from,
+			To:        to,
+		}
+
+		testCases := []struct {
+			name          string
+			accountID     int64
+			query         url.Values
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:      "OK",
+				accountID: account.ID,
+				query:     url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						AccountStatementTx(gomock.Any(), gomock.Eq(statementArg)).
+						Times(1).
+						Return(statement, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var got statementResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &got)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(got.OpeningBalance).To(Equal(statement.OpeningBalance))
+					Expect(got.OpeningBalanceDecimal).To(Equal("1.00"))
+					Expect(got.ClosingBalance).To(Equal(statement.ClosingBalance))
+					Expect(got.ClosingBalanceDecimal).To(Equal("1.20"))
+					Expect(got.Entries).To(HaveLen(2))
+					Expect(got.Entries[0].Balance).To(Equal(int64(70)))
+					Expect(got.Entries[0].AmountDecimal).To(Equal("-0.30"))
+					Expect(got.Entries[0].BalanceDecimal).To(Equal("0.70"))
+				},
+			},
+
+			{
+				name:      "CSV",
+				accountID: account.ID,
+				query:     url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}, "format": {"csv"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						AccountStatementTx(gomock.Any(), gomock.Eq(statementArg)).
+						Times(1).
+						Return(statement, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					Expect(recorder.Header().Get("Content-Type")).To(Equal("text/csv"))
+
+					records, err := csv.NewReader(recorder.Body).ReadAll()
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(records).To(HaveLen(5))
+					Expect(records[1][0]).To(Equal("opening_balance"))
+					Expect(records[1][4]).To(Equal("100"))
+					Expect(records[1][9]).To(Equal("1.00"))
+					Expect(records[2]).To(Equal([]string{"entry", "1", from.Add(time.Hour).Format(time.RFC3339), "-30", "70", account.Currency, "Rent", "INV-1", "-0.30", "0.70"}))
+					Expect(records[4][0]).To(Equal("closing_balance"))
+					Expect(records[4][4]).To(Equal("120"))
+					Expect(records[4][9]).To(Equal("1.20"))
+				},
+			},
+
+			{
+				name:      "Search",
+				accountID: account.ID,
+				query:     url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}, "search": {"rent"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := statementArg
+					arg.Search = "rent"
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						AccountStatementTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(statement, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:      "Unauthorized User",
+				accountID: account.ID,
+				query:     url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, "unauthorized", time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						AccountStatementTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:      "Not Found",
+				accountID: account.ID,
+				query:     url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(db.Account{}, sql.ErrNoRows)
+					store.EXPECT().
+						AccountStatementTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				},
+			},
+
+			{
+				name:      "Invalid Period",
+				accountID: account.ID,
+				query:     url.Values{"from": {to.Format(time.RFC3339)}, "to": {from.Format(time.RFC3339)}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:      "Invalid Format",
+				accountID: account.ID,
+				query:     url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}, "format": {"pdf"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:      "Internal Error",
+				accountID: account.ID,
+				query:     url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+						Times(1).
+						Return(account, nil)
+					store.EXPECT().
+						AccountStatementTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.AccountStatementTxResult{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				// create mock store
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				// start test server and send request
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				url := fmt.Sprintf("/accounts/%d/statement?%s", tc.accountID, tc.query.Encode())
+				request, err := http.NewRequest(http.MethodGet, url, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+
+				// call the server
+				server.router.ServeHTTP(recorder, request)
+				// check the response
+				tc.checkResponse(recorder)
+			})
+		}
+	})
+})
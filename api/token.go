@@ -0,0 +1,99 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// renewAccessTokenRequest defines the request body for renewAccessToken API
+type renewAccessTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// renewAccessTokenResponse defines the response body for renewAccessToken API
+type renewAccessTokenResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+}
+
+// renewAccessToken implements the API that issues a new access token for a valid refresh token
+func (server *Server) renewAccessToken(ctx *gin.Context) {
+	var req renewAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+	if err == nil {
+		err = refreshPayload.CheckType(token.TokenTypeRefresh)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, authErrorResponse(tokenErrorCode(err), err))
+		return
+	}
+
+	// The refresh token is only accepted while the session it was issued with is stored and active
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("session not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.IsBlocked {
+		err := fmt.Errorf("%w: session is blocked", token.ErrRevoked)
+		ctx.JSON(http.StatusUnauthorized, authErrorResponse(authErrorRevokedToken, err))
+		return
+	}
+
+	if session.Username != refreshPayload.Username {
+		err := errors.New("session does not belong to the token user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if session.RefreshToken != req.RefreshToken {
+		err := errors.New("mismatched session token")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		err := fmt.Errorf("session expired at %s", session.ExpiresAt.Format(time.RFC3339))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// The role is reloaded so that a role change takes effect on the next renewal
+	user, err := server.store.GetUser(ctx, refreshPayload.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("user not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, model.Role(user.Role), token.TokenTypeAccess, server.config.AccessToken)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := renewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("API tests", func() {
+	Context("renewAccessToken API", func() {
+		userName := util.GetRandomOwnerName()
+
+		newSession := func(refreshToken string, payload *token.Payload) db.Session {
+			return db.Session{
+				ID:           payload.ID,
+				Username:     payload.Username,
+				RefreshToken: refreshToken,
+				ExpiresAt:    payload.ExpiredAt,
+			}
+		}
+
+		testCases := []struct {
+			name          string
+			duration      time.Duration
+			body          func(refreshToken string) gin.H
+			buildStubs    func(store *mockdb.MockStore, refreshToken string, payload *token.Payload)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:     "OK",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+						Times(1).
+						Return(newSession(refreshToken, payload), nil)
+					store.EXPECT().
+						GetUser(gomock.Any(), gomock.Eq(userName)).
+						Times(1).
+						Return(db.User{Username: userName, Role: string(model.RoleCustomer)}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp renewAccessTokenResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.AccessToken).NotTo(BeEmpty())
+				},
+			},
+
+			{
+				name:     "Bad Request",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:     "Expired Refresh Token",
+				duration: -time.Minute,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:     "Session Not Found",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+						Times(1).
+						Return(db.Session{}, sql.ErrNoRows)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:     "Blocked Session",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					session := newSession(refreshToken, payload)
+					session.IsBlocked = true
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+						Times(1).
+						Return(session, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:     "Session Of Another User",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					session := newSession(refreshToken, payload)
+					session.Username = "unauthorized"
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+						Times(1).
+						Return(session, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:     "Mismatched Session Token",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+						Times(1).
+						Return(newSession("another-token", payload), nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:     "User Not Found",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+						Times(1).
+						Return(newSession(refreshToken, payload), nil)
+					store.EXPECT().
+						GetUser(gomock.Any(), gomock.Eq(userName)).
+						Times(1).
+						Return(db.User{}, sql.ErrNoRows)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:     "Internal Error",
+				duration: time.Hour,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+					store.EXPECT().
+						GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+						Times(1).
+						Return(db.Session{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				// create mock store
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+
+				// start test server and send request
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				refreshToken, payload, err := server.tokenMaker.CreateToken(userName, model.RoleCustomer, token.TokenTypeRefresh, tc.duration)
+				Expect(err).ShouldNot(HaveOccurred())
+				tc.buildStubs(store, refreshToken, payload)
+
+				body, err := json.Marshal(tc.body(refreshToken))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				url := "/tokens/renew_access"
+				request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				// call the server
+				server.router.ServeHTTP(recorder, request)
+				// check the response
+				tc.checkResponse(recorder)
+			})
+		}
+
+		It("Test renew access token uses the current role", func() {
+			controller := gomock.NewController(GinkgoT())
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			// The user logged in as a customer and was made an admin afterwards
+			refreshToken, payload, err := server.tokenMaker.CreateToken(userName, model.RoleCustomer, token.TokenTypeRefresh, time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			store.EXPECT().
+				GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+				Times(1).
+				Return(newSession(refreshToken, payload), nil)
+			store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq(userName)).
+				Times(1).
+				Return(db.User{Username: userName, Role: string(model.RoleAdmin)}, nil)
+
+			body, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(body))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			server.router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var rsp renewAccessTokenResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			accessPayload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(accessPayload.Role).To(Equal(model.RoleAdmin))
+		})
+
+		It("Test renew access token rejects an access token", func() {
+			controller := gomock.NewController(GinkgoT())
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			store.EXPECT().
+				GetSession(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			accessToken, _, err := server.tokenMaker.CreateToken(userName, model.RoleCustomer, token.TokenTypeAccess, time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			body, err := json.Marshal(gin.H{"refresh_token": accessToken})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(body))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			server.router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -2,20 +2,35 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/exchange"
 	m "github.com/Petatron/bank-simulator-backend/model"
 	"github.com/Petatron/bank-simulator-backend/token"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
-// transferRequest defines the body for transfer API request
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
+// transferRequest defines the body for transfer API request.
+// Currency is the currency of the source account, the amount is converted if the destination account uses another one.
+// Amount is a decimal string in that currency or a whole number of its minor units.
+// Description, ExternalReference and Metadata are optional, Metadata must be a JSON object.
 type transferRequest struct {
-	FromAccountID int64          `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64          `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64          `json:"amount" binding:"required,gt=0"`
-	Currency      m.CurrencyType `json:"currency" binding:"required,currency"`
+	FromAccountID     int64           `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID       int64           `json:"to_account_id" binding:"required,min=1"`
+	Amount            amountValue     `json:"amount"`
+	Currency          m.CurrencyType  `json:"currency" binding:"required,currency"`
+	Description       string          `json:"description" binding:"max=255"`
+	ExternalReference string          `json:"external_reference" binding:"max=100"`
+	Metadata          json.RawMessage `json:"metadata" binding:"max=4096"`
 }
 
 // createTransfer implements the API that creates a new transfer
@@ -25,6 +40,28 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	if err := server.checkCurrency(req.Currency); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, err := server.positiveAmount(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := checkMetadata(req.Metadata); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		err := fmt.Errorf("idempotency key must be at most %d characters", maxIdempotencyKeyLength)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
 	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
 	if !valid {
@@ -33,34 +70,226 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 
 	// createTransfer API rule: A logged-in user can only create a transfer for the accounts they own
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("the account does not belong to the authenticated user")
+	if err := authorizeAccountOwner(authPayload, fromAccount); err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	toAccount, valid := server.existingAccount(ctx, req.ToAccountID)
 	if !valid {
 		return
 	}
 
 	// createTransfer API rule: A logged-in user can only create a transfer for the accounts they own
 	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+		FromAccountID:     req.FromAccountID,
+		ToAccountID:       req.ToAccountID,
+		Amount:            amount,
+		IdempotencyKey:    idempotencyKey,
+		Description:       req.Description,
+		ExternalReference: req.ExternalReference,
+		Metadata:          req.Metadata,
 	}
 
-	result, err := server.store.TransferTx(ctx, arg)
+	var result db.TransferTxResult
+	if toAccount.Currency == string(req.Currency) {
+		result, err = server.store.TransferTx(ctx, arg)
+	} else {
+		fxArg, ok := server.convertTransfer(ctx, arg, req.Currency, m.CurrencyType(toAccount.Currency))
+		if !ok {
+			return
+		}
+		result, err = server.store.FXTransferTx(ctx, fxArg)
+	}
 	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountClosed) || errors.Is(err, db.ErrAccountFrozen) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, server.newTransferResponse(result, string(req.Currency), toAccount.Currency))
 }
 
-// validAccount checks if the given account is valid(availability, currency validation)
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency m.CurrencyType) (db.Account, bool) {
+// transferResponse is the result of a transfer with its amounts as decimal strings in the major unit of each currency
+type transferResponse struct {
+	db.TransferTxResult
+	AmountDecimal   string `json:"amount_decimal,omitempty"`
+	ToAmountDecimal string `json:"to_amount_decimal,omitempty"`
+}
+
+// newTransferResponse creates the response of a transfer from an account in one currency to an account in another
+func (server *Server) newTransferResponse(result db.TransferTxResult, fromCurrency, toCurrency string) transferResponse {
+	return transferResponse{
+		TransferTxResult: result,
+		AmountDecimal:    server.formatAmount(result.Transfer.Amount, fromCurrency),
+		ToAmountDecimal:  server.formatAmount(result.Transfer.ToAmount, toCurrency),
+	}
+}
+
+// transferItemResponse is a transfer record with its amounts as decimal strings in the major unit of each currency
+type transferItemResponse struct {
+	db.Transfer
+	AmountDecimal   string `json:"amount_decimal,omitempty"`
+	ToAmountDecimal string `json:"to_amount_decimal,omitempty"`
+}
+
+// newTransferItemResponse creates the response of a transfer record from an account in one currency to an account in another
+func (server *Server) newTransferItemResponse(transfer db.Transfer, fromCurrency, toCurrency string) transferItemResponse {
+	return transferItemResponse{
+		Transfer:        transfer,
+		AmountDecimal:   server.formatAmount(transfer.Amount, fromCurrency),
+		ToAmountDecimal: server.formatAmount(transfer.ToAmount, toCurrency),
+	}
+}
+
+const (
+	transferDirectionIn  = "in"
+	transferDirectionOut = "out"
+)
+
+// listTransfersRequest defines the query for listTransfers API request.
+// Every filter is optional. Direction, AccountID, Currency and the amount range apply to the side of the transfer
+// on the accounts of the caller, so an incoming cross-currency transfer is matched on the amount it credited.
+// MinAmount and MaxAmount are decimal numbers in the major unit of Currency, which they require, or whole numbers of minor units.
+// From and To are RFC 3339 timestamps, the range is [from, to).
+type listTransfersRequest struct {
+	AccountID         int64          `form:"account_id" binding:"omitempty,min=1"`
+	Direction         string         `form:"direction" binding:"omitempty,oneof=in out"`
+	MinAmount         amountValue    `form:"min_amount"`
+	MaxAmount         amountValue    `form:"max_amount"`
+	Currency          m.CurrencyType `form:"currency" binding:"omitempty,currency"`
+	From              time.Time      `form:"from"`
+	To                time.Time      `form:"to" binding:"omitempty,gtfield=From"`
+	ExternalReference string         `form:"external_reference" binding:"max=100"`
+	Search            string         `form:"search" binding:"max=255"`
+	pageRequest
+}
+
+// listTransfers implements the API that searches the transfers from and to the accounts of the caller, newest first.
+// Pages are keyset paginated, the next_cursor of a page is passed as cursor to get the next one.
+func (server *Server) listTransfers(ctx *gin.Context) {
+	var req listTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Currency != "" {
+		if err := server.checkCurrency(req.Currency); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if (req.MinAmount.isDecimal() || req.MaxAmount.isDecimal()) && req.Currency == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "currency is required to filter on a decimal amount"})
+		return
+	}
+	minAmount, err := server.optionalAmount(req.MinAmount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	maxAmount, err := server.optionalAmount(req.MaxAmount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if maxAmount != 0 && maxAmount < minAmount {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "max_amount cannot be less than min_amount"})
+		return
+	}
+
+	beforeID, err := req.after()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// listTransfers API rule: A logged-in user can only list the transfers of the accounts they own
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.SearchTransfersParams{
+		Owner:             authPayload.Username,
+		Outgoing:          req.Direction != transferDirectionIn,
+		Incoming:          req.Direction != transferDirectionOut,
+		AccountID:         sql.NullInt64{Int64: req.AccountID, Valid: req.AccountID != 0},
+		Currency:          sql.NullString{String: string(req.Currency), Valid: req.Currency != ""},
+		MinAmount:         sql.NullInt64{Int64: minAmount, Valid: minAmount != 0},
+		MaxAmount:         sql.NullInt64{Int64: maxAmount, Valid: maxAmount != 0},
+		FromTime:          sql.NullTime{Time: req.From, Valid: !req.From.IsZero()},
+		ToTime:            sql.NullTime{Time: req.To, Valid: !req.To.IsZero()},
+		ExternalReference: sql.NullString{String: req.ExternalReference, Valid: req.ExternalReference != ""},
+		Search:            sql.NullString{String: req.Search, Valid: req.Search != ""},
+		BeforeID:          beforeID,
+		Limit:             req.limit(),
+	}
+
+	transfers, err := server.store.SearchTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var rsp pageResponse[transferItemResponse]
+	transfers, rsp.NextCursor = paginate(req.pageRequest, transfers, func(row db.SearchTransfersRow) int64 {
+		return row.Transfer.ID
+	})
+	rsp.Items = make([]transferItemResponse, len(transfers))
+	for i, row := range transfers {
+		rsp.Items[i] = server.newTransferItemResponse(row.Transfer, row.FromCurrency, row.ToCurrency)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// convertTransfer converts the amount of a transfer into the currency of the destination account.
+// The amounts are in minor units, so the exponents of both currencies come from the currency registry.
+func (server *Server) convertTransfer(ctx *gin.Context, arg db.TransferTxParams, from, to m.CurrencyType) (db.FXTransferTxParams, bool) {
+	rate, err := server.fxRates.Rate(from, to)
+	if err != nil {
+		if errors.Is(err, exchange.ErrRateNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return db.FXTransferTxParams{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.FXTransferTxParams{}, false
+	}
+
+	fromCurrency, ok := server.currencies.Lookup(from)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("unknown currency %q", from)))
+		return db.FXTransferTxParams{}, false
+	}
+	toCurrency, ok := server.currencies.Lookup(to)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("unknown currency %q", to)))
+		return db.FXTransferTxParams{}, false
+	}
+
+	toAmount, err := rate.Convert(arg.Amount, fromCurrency.Exponent, toCurrency.Exponent)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.FXTransferTxParams{}, false
+	}
+	if toAmount <= 0 {
+		err := fmt.Errorf("amount is too small to convert from %s to %s", from, to)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.FXTransferTxParams{}, false
+	}
+
+	return db.FXTransferTxParams{
+		TransferTxParams: arg,
+		ToAmount:         toAmount,
+		ExchangeRate:     rate.String(),
+	}, true
+}
+
+// existingAccount fetches the given account and responds with an error if it cannot be found or is closed
+func (server *Server) existingAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -71,6 +300,22 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency m
 		return account, false
 	}
 
+	if account.Status == string(m.AccountStatusClosed) {
+		err := fmt.Errorf("%w: account %d", db.ErrAccountClosed, account.ID)
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
+
+// validAccount checks if the given account is valid(availability, open, currency validation)
+func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency m.CurrencyType) (db.Account, bool) {
+	account, valid := server.existingAccount(ctx, accountID)
+	if !valid {
+		return account, false
+	}
+
 	if account.Currency != string(currency) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "account currency does not match or incorrect"})
 		return account, false
@@ -78,3 +323,16 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency m
 
 	return account, true
 }
+
+// checkMetadata checks that the metadata of a transfer, if it is set, is a JSON object
+func checkMetadata(metadata json.RawMessage) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal(metadata, &object); err != nil || object == nil {
+		return errors.New("metadata must be a JSON object")
+	}
+	return nil
+}
@@ -2,12 +2,16 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"github.com/Petatron/bank-simulator-backend/currency"
 	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/exchange"
+	"github.com/Petatron/bank-simulator-backend/model"
 	"github.com/Petatron/bank-simulator-backend/token"
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo"
@@ -15,6 +19,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"time"
 )
 
@@ -26,11 +31,12 @@ var _ = Describe("API tests", func() {
 		toAccount := getRandomAccount(toUserName)
 
 		testCases := []struct {
-			name          string
-			body          gin.H
-			setupAuth     func(request *http.Request, tokenMaker token.Maker)
-			buildStubs    func(store *mockdb.MockStore)
-			checkResponse func(recorder *httptest.ResponseRecorder)
+			name           string
+			body           gin.H
+			idempotencyKey string
+			setupAuth      func(request *http.Request, tokenMaker token.Maker)
+			buildStubs     func(store *mockdb.MockStore)
+			checkResponse  func(recorder *httptest.ResponseRecorder)
 		}{
 			{
 				name: "OK",
@@ -62,35 +68,489 @@ var _ = Describe("API tests", func() {
 						Amount:        10,
 					}
 					store.EXPECT().
-						TransferTx(gomock.Any(), gomock.Eq(arg)).
-						Times(1)
+						TransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Decimal Amount",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          "12.34",
+					"currency":        "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					arg := db.TransferTxParams{
+						FromAccountID: fromAccount.ID,
+						ToAccountID:   toAccount.ID,
+						Amount:        1234,
+					}
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(db.TransferTxResult{Transfer: db.Transfer{Amount: 1234, ToAmount: 1234}}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp transferResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.Transfer.Amount).To(Equal(int64(1234)))
+					Expect(rsp.AmountDecimal).To(Equal("12.34"))
+					Expect(rsp.ToAmountDecimal).To(Equal("12.34"))
+				},
+			},
+
+			{
+				name: "Decimal Amount Too Precise",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          "12.345",
+					"currency":        "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Any()).
+						Times(0)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Negative Amount",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          "-1.00",
+					"currency":        "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Fractional Minor Units",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10.5,
+					"currency":        "USD",
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Bad Request",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					toAccount.Currency = "USD"
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Internal Server Error",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.TransferTxResult{}, sql.ErrConnDone)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+
+			{
+				name: "With Details",
+				body: gin.H{
+					"from_account_id":    fromAccount.ID,
+					"to_account_id":      toAccount.ID,
+					"amount":             10,
+					"currency":           "USD",
+					"description":        "Rent for March",
+					"external_reference": "INV-1001",
+					"metadata":           gin.H{"category": "housing"},
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					arg := db.TransferTxParams{
+						FromAccountID:     fromAccount.ID,
+						ToAccountID:       toAccount.ID,
+						Amount:            10,
+						Description:       "Rent for March",
+						ExternalReference: "INV-1001",
+						Metadata:          json.RawMessage(`{"category":"housing"}`),
+					}
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Metadata Not An Object",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+					"metadata":        []string{"housing"},
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Idempotency Key",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+				idempotencyKey: "transfer-key",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					arg := db.TransferTxParams{
+						FromAccountID:  fromAccount.ID,
+						ToAccountID:    toAccount.ID,
+						Amount:         10,
+						IdempotencyKey: "transfer-key",
+					}
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name: "Idempotency Key Reused",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+				idempotencyKey: "transfer-key",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.TransferTxResult{}, db.ErrIdempotencyKeyReused)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				},
+			},
+
+			{
+				name: "Idempotency Key Too Long",
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+				idempotencyKey: util.GetRandomStringWithLength(maxIdempotencyKeyLength + 1),
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Any()).
+						Times(0)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Insufficient Funds",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+				},
+			},
+
+			{
+				name: "From Account Closed",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+
+				buildStubs: func(store *mockdb.MockStore) {
+					closedAccount := fromAccount
+					closedAccount.Currency = "USD"
+					closedAccount.Status = string(model.AccountStatusClosed)
+					closedAccount.ClosedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(closedAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(0)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+				},
+			},
+
+			{
+				name: "To Account Closed",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "USD"
+					closedAccount := toAccount
+					closedAccount.Currency = "USD"
+					closedAccount.Status = string(model.AccountStatusClosed)
+					closedAccount.ClosedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(closedAccount, nil)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
 				},
 
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
-					Expect(recorder.Code).To(Equal(http.StatusOK))
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
 				},
 			},
 
 			{
-				name: "Bad Request",
+				name: "Account Closed During Transfer",
 				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
 					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
 				},
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+
 				buildStubs: func(store *mockdb.MockStore) {
 					fromAccount.Currency = "USD"
 					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(1).
+						Return(toAccount, nil)
 					store.EXPECT().
 						TransferTx(gomock.Any(), gomock.Any()).
-						Times(0)
+						Times(1).
+						Return(db.TransferTxResult{}, db.ErrAccountClosed)
 				},
 
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
-					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
 				},
 			},
 
 			{
-				name: "Internal Server Error",
+				name: "Account Frozen",
 				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
 					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
 				},
@@ -116,11 +576,11 @@ var _ = Describe("API tests", func() {
 					store.EXPECT().
 						TransferTx(gomock.Any(), gomock.Any()).
 						Times(1).
-						Return(db.TransferTxResult{}, sql.ErrConnDone)
+						Return(db.TransferTxResult{}, db.ErrAccountFrozen)
 				},
 
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
-					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
 				},
 			},
 
@@ -211,6 +671,33 @@ var _ = Describe("API tests", func() {
 				},
 			},
 
+			{
+				name: "Admin Cannot Move Funds Of Another User",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizationsWithRole(request, tokenMaker, authorizationTypeBearer, "admin", model.RoleAdmin, time.Minute)
+				},
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          10,
+					"currency":        "USD",
+				},
+
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
 			{
 				name: "Account Currency Mismatch",
 				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
@@ -223,6 +710,39 @@ var _ = Describe("API tests", func() {
 					"currency":        "USD",
 				},
 
+				buildStubs: func(store *mockdb.MockStore) {
+					fromAccount.Currency = "EUR"
+					toAccount.Currency = "USD"
+
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+						Times(1).
+						Return(fromAccount, nil)
+					store.EXPECT().
+						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+						Times(0)
+					store.EXPECT().
+						TransferTx(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Cross Currency",
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+				},
+				body: gin.H{
+					"from_account_id": fromAccount.ID,
+					"to_account_id":   toAccount.ID,
+					"amount":          100,
+					"currency":        "USD",
+				},
+
 				buildStubs: func(store *mockdb.MockStore) {
 					fromAccount.Currency = "USD"
 					toAccount.Currency = "EUR"
@@ -235,13 +755,25 @@ var _ = Describe("API tests", func() {
 						GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
 						Times(1).
 						Return(toAccount, nil)
+					arg := db.FXTransferTxParams{
+						TransferTxParams: db.TransferTxParams{
+							FromAccountID: fromAccount.ID,
+							ToAccountID:   toAccount.ID,
+							Amount:        100,
+						},
+						ToAmount:     92,
+						ExchangeRate: "0.92",
+					}
+					store.EXPECT().
+						FXTransferTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
 					store.EXPECT().
 						TransferTx(gomock.Any(), gomock.Any()).
 						Times(0)
 				},
 
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
-					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(recorder.Code).To(Equal(http.StatusOK))
 				},
 			},
 		}
@@ -268,6 +800,9 @@ var _ = Describe("API tests", func() {
 				request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
 				Expect(err).ShouldNot(HaveOccurred())
 
+				if tc.idempotencyKey != "" {
+					request.Header.Set(idempotencyKeyHeader, tc.idempotencyKey)
+				}
 				tc.setupAuth(request, server.tokenMaker)
 
 				// call the server
@@ -276,5 +811,313 @@ var _ = Describe("API tests", func() {
 				tc.checkResponse(recorder)
 			})
 		}
+
+		It("Test cross currency transfer scales by the currency exponents", func() {
+			controller := gomock.NewController(GinkgoT())
+			defer controller.Finish()
+
+			fromAccount := getRandomAccount(fromUserName)
+			fromAccount.Currency = "USD"
+			toAccount := getRandomAccount(toUserName)
+			toAccount.Currency = "JPY"
+
+			store := mockdb.NewMockStore(controller)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+				Times(1).
+				Return(fromAccount, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+				Times(1).
+				Return(toAccount, nil)
+			// 12.34 USD at 149.5 is 1844.83 JPY, which has no minor unit
+			store.EXPECT().
+				FXTransferTx(gomock.Any(), gomock.Eq(db.FXTransferTxParams{
+					TransferTxParams: db.TransferTxParams{
+						FromAccountID: fromAccount.ID,
+						ToAccountID:   toAccount.ID,
+						Amount:        1234,
+					},
+					ToAmount:     1845,
+					ExchangeRate: "149.5",
+				})).
+				Times(1)
+
+			server := newTestServer(store)
+			_, err := server.currencies.Put(context.Background(), currency.Currency{Code: "JPY", Exponent: 0, Enabled: true})
+			Expect(err).ShouldNot(HaveOccurred())
+			server.fxRates, err = exchange.NewStaticRateProvider([]exchange.Quote{{From: model.USD, To: "JPY", Rate: "149.5"}})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			body, err := json.Marshal(gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          "12.34",
+				"currency":        "USD",
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(body))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			addAuthorizations(request, server.tokenMaker, authorizationTypeBearer, fromUserName, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("listTransfers API", func() {
+		userName := util.GetRandomOwnerName()
+		transfers := []db.SearchTransfersRow{
+			{Transfer: db.Transfer{ID: 30, Amount: 1250, ToAmount: 1150}, FromCurrency: "USD", ToCurrency: "EUR"},
+			{Transfer: db.Transfer{ID: 20}, FromCurrency: "USD", ToCurrency: "USD"},
+			{Transfer: db.Transfer{ID: 10}, FromCurrency: "EUR", ToCurrency: "EUR"},
+		}
+
+		testCases := []struct {
+			name          string
+			query         url.Values
+			setupAuth     func(request *http.Request, tokenMaker token.Maker)
+			buildStubs    func(store *mockdb.MockStore)
+			checkResponse func(recorder *httptest.ResponseRecorder)
+		}{
+			{
+				name:  "First Page",
+				query: url.Values{"page_size": {"2"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.SearchTransfersParams{
+						Owner:    userName,
+						Outgoing: true,
+						Incoming: true,
+						Limit:    3,
+					}
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(transfers, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp pageResponse[transferItemResponse]
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.Items).To(HaveLen(2))
+					Expect(rsp.Items[0].ID).To(Equal(int64(30)))
+					Expect(rsp.Items[0].AmountDecimal).To(Equal("12.50"))
+					Expect(rsp.Items[0].ToAmountDecimal).To(Equal("11.50"))
+					Expect(rsp.NextCursor).To(Equal(encodeCursor(pageCursor{ID: 20})))
+				},
+			},
+
+			{
+				name: "Filters And Cursor",
+				query: url.Values{
+					"account_id": {"7"},
+					"direction":  {"in"},
+					"min_amount": {"10"},
+					"max_amount": {"100"},
+					"currency":   {"EUR"},
+					"from":       {"2024-01-01T00:00:00Z"},
+					"to":         {"2024-02-01T00:00:00Z"},
+					"search":     {"rent"},
+					"cursor":     {encodeCursor(pageCursor{ID: 20})},
+					"page_size":  {"2"},
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					arg := db.SearchTransfersParams{
+						Owner:     userName,
+						Incoming:  true,
+						AccountID: sql.NullInt64{Int64: 7, Valid: true},
+						Currency:  sql.NullString{String: "EUR", Valid: true},
+						MinAmount: sql.NullInt64{Int64: 10, Valid: true},
+						MaxAmount: sql.NullInt64{Int64: 100, Valid: true},
+						FromTime:  sql.NullTime{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						ToTime:    sql.NullTime{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						Search:    sql.NullString{String: "rent", Valid: true},
+						BeforeID:  sql.NullInt64{Int64: 20, Valid: true},
+						Limit:     3,
+					}
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(transfers[2:], nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp pageResponse[transferItemResponse]
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.Items).To(HaveLen(1))
+					Expect(rsp.NextCursor).To(BeEmpty())
+				},
+			},
+
+			{
+				name:  "Invalid Cursor",
+				query: url.Values{"cursor": {"not-a-cursor"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:  "Invalid Amount Range",
+				query: url.Values{"min_amount": {"100"}, "max_amount": {"10"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name: "Decimal Amount Range",
+				query: url.Values{
+					"min_amount": {"10.50"},
+					"max_amount": {"100.00"},
+					"currency":   {"USD"},
+				},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Cond(func(arg db.SearchTransfersParams) bool {
+							return arg.MinAmount == sql.NullInt64{Int64: 1050, Valid: true} &&
+								arg.MaxAmount == sql.NullInt64{Int64: 10000, Valid: true}
+						})).
+						Times(1).
+						Return([]db.SearchTransfersRow{}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+				},
+			},
+
+			{
+				name:  "Decimal Amount Without Currency",
+				query: url.Values{"min_amount": {"10.50"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:  "Decimal Amount Too Precise",
+				query: url.Values{"min_amount": {"10.505"}, "currency": {"USD"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:  "Invalid Direction",
+				query: url.Values{"direction": {"sideways"}},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				},
+			},
+
+			{
+				name:  "No Authorization",
+				query: url.Values{},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:  "Internal Error",
+				query: url.Values{},
+				setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+					addAuthorizations(request, tokenMaker, authorizationTypeBearer, userName, time.Minute)
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						SearchTransfers(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return([]db.SearchTransfersRow{}, sql.ErrConnDone)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+				tc.buildStubs(store)
+
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				request, err := http.NewRequest(http.MethodGet, "/transfers?"+tc.query.Encode(), nil)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				tc.setupAuth(request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(recorder)
+			})
+		}
 	})
 })
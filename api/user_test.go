@@ -2,13 +2,17 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/Petatron/bank-simulator-backend/token"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -205,10 +209,51 @@ var _ = Describe("API tests", func() {
 						GetUser(gomock.Any(), gomock.Eq(user.Username)).
 						Times(1).
 						Return(user, nil)
+					store.EXPECT().
+						CreateSession(gomock.Any(), gomock.Any()).
+						Times(1).
+						DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+							return db.Session{
+								ID:           arg.ID,
+								Username:     arg.Username,
+								RefreshToken: arg.RefreshToken,
+								ExpiresAt:    arg.ExpiresAt,
+							}, nil
+						})
 				},
 
 				checkResponse: func(recorder *httptest.ResponseRecorder) {
 					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					var rsp loginUserResponse
+					err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(rsp.AccessToken).NotTo(BeEmpty())
+					Expect(rsp.RefreshToken).NotTo(BeEmpty())
+					Expect(rsp.SessionID).NotTo(Equal(uuid.Nil))
+					Expect(rsp.RefreshTokenExpiresAt).To(BeTemporally(">", rsp.AccessTokenExpiresAt))
+				},
+			},
+
+			{
+				name: "Create Session Error",
+				body: gin.H{
+					"username": user.Username,
+					"password": password,
+				},
+				buildStubs: func(store *mockdb.MockStore) {
+					store.EXPECT().
+						GetUser(gomock.Any(), gomock.Eq(user.Username)).
+						Times(1).
+						Return(user, nil)
+					store.EXPECT().
+						CreateSession(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.Session{}, sql.ErrConnDone)
+				},
+
+				checkResponse: func(recorder *httptest.ResponseRecorder) {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
 				},
 			},
 
@@ -316,6 +361,165 @@ var _ = Describe("API tests", func() {
 	})
 })
 
+var _ = Describe("API tests", func() {
+	Context("logoutUser API", func() {
+		userName := util.GetRandomOwnerName()
+
+		testCases := []struct {
+			name          string
+			refreshUser   string
+			auth          bool
+			body          func(refreshToken string) gin.H
+			buildStubs    func(store *mockdb.MockStore, refreshPayload *token.Payload)
+			checkResponse func(recorder *httptest.ResponseRecorder, server *Server, accessPayload, refreshPayload *token.Payload)
+		}{
+			{
+				name:        "OK",
+				refreshUser: userName,
+				auth:        true,
+				body: func(refreshToken string) gin.H {
+					return nil
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshPayload *token.Payload) {
+					store.EXPECT().
+						BlockSession(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server, accessPayload, refreshPayload *token.Payload) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					revoked, err := server.revocations.IsRevoked(context.Background(), accessPayload.ID)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(revoked).To(BeTrue())
+				},
+			},
+
+			{
+				name:        "OK With Refresh Token",
+				refreshUser: userName,
+				auth:        true,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshPayload *token.Payload) {
+					store.EXPECT().
+						BlockSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).
+						Times(1).
+						Return(db.Session{ID: refreshPayload.ID, IsBlocked: true}, nil)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server, accessPayload, refreshPayload *token.Payload) {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					revoked, err := server.revocations.IsRevoked(context.Background(), accessPayload.ID)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(revoked).To(BeTrue())
+
+					revoked, err = server.revocations.IsRevoked(context.Background(), refreshPayload.ID)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(revoked).To(BeTrue())
+				},
+			},
+
+			{
+				name:        "Refresh Token Of Another User",
+				refreshUser: "unauthorized",
+				auth:        true,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshPayload *token.Payload) {
+					store.EXPECT().
+						BlockSession(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server, accessPayload, refreshPayload *token.Payload) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+
+					revoked, err := server.revocations.IsRevoked(context.Background(), accessPayload.ID)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(revoked).To(BeFalse())
+				},
+			},
+
+			{
+				name:        "Session Not Found",
+				refreshUser: userName,
+				auth:        true,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshPayload *token.Payload) {
+					store.EXPECT().
+						BlockSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).
+						Times(1).
+						Return(db.Session{}, sql.ErrNoRows)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server, accessPayload, refreshPayload *token.Payload) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+
+			{
+				name:        "No Authorization",
+				refreshUser: userName,
+				auth:        false,
+				body: func(refreshToken string) gin.H {
+					return gin.H{"refresh_token": refreshToken}
+				},
+				buildStubs: func(store *mockdb.MockStore, refreshPayload *token.Payload) {
+					store.EXPECT().
+						BlockSession(gomock.Any(), gomock.Any()).
+						Times(0)
+				},
+				checkResponse: func(recorder *httptest.ResponseRecorder, server *Server, accessPayload, refreshPayload *token.Payload) {
+					Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				},
+			},
+		}
+
+		for i := range testCases {
+			tc := testCases[i]
+
+			It(fmt.Sprintf("Test case #%d: %s", i, tc.name), func() {
+				// create mock store
+				controller := gomock.NewController(GinkgoT())
+				defer controller.Finish()
+
+				store := mockdb.NewMockStore(controller)
+
+				// start test server and send request
+				server := newTestServer(store)
+				recorder := httptest.NewRecorder()
+
+				accessToken, accessPayload, err := server.tokenMaker.CreateToken(userName, model.RoleCustomer, token.TokenTypeAccess, time.Minute)
+				Expect(err).ShouldNot(HaveOccurred())
+				refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(tc.refreshUser, model.RoleCustomer, token.TokenTypeRefresh, time.Hour)
+				Expect(err).ShouldNot(HaveOccurred())
+				tc.buildStubs(store, refreshPayload)
+
+				var body []byte
+				if data := tc.body(refreshToken); data != nil {
+					body, err = json.Marshal(data)
+					Expect(err).ShouldNot(HaveOccurred())
+				}
+
+				url := "/users/logout"
+				request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				if tc.auth {
+					request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+				}
+
+				// call the server
+				server.router.ServeHTTP(recorder, request)
+				// check the response
+				tc.checkResponse(recorder, server, accessPayload, refreshPayload)
+			})
+		}
+	})
+})
+
 func randomUserWithPassword() (password string, user db.User) {
 	pass := util.GetRandomStringWithLength(10)
 	hashedPass, err := util.HashPassword(pass)
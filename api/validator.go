@@ -1,14 +1,25 @@
 package api
 
 import (
+	"fmt"
+
+	"github.com/Petatron/bank-simulator-backend/currency"
 	m "github.com/Petatron/bank-simulator-backend/model"
 	"github.com/go-playground/validator/v10"
 )
 
-// validCurrency is a validator.Func that checks if the currency is valid
+// validCurrency is a validator.Func that checks if the currency is a well-formed ISO 4217 code.
+// The gin validator is shared by every server, so whether the currency is supported is left to checkCurrency,
+// which looks it up in the registry of the server handling the request.
 var validCurrency validator.Func = func(fl validator.FieldLevel) bool {
-	if currency, ok := fl.Field().Interface().(m.CurrencyType); ok {
-		return currency.IsValid()
+	code, ok := fl.Field().Interface().(m.CurrencyType)
+	return ok && code.IsValid()
+}
+
+// checkCurrency returns an error unless the currency is registered and enabled in the registry of the server
+func (server *Server) checkCurrency(code m.CurrencyType) error {
+	if !currency.IsSupported(server.currencies, code) {
+		return fmt.Errorf("currency %q is not supported", code)
 	}
-	return false
+	return nil
 }
@@ -0,0 +1,240 @@
+package currency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/model"
+)
+
+// MaxExponent is the largest minor-unit exponent of a currency, ISO 4217 does not define larger ones
+const MaxExponent = 4
+
+var (
+	// ErrInvalidCurrency is returned when a currency has a malformed code or an exponent out of range
+	ErrInvalidCurrency = errors.New("invalid currency")
+	// ErrExponentChange is returned when the exponent of a registered currency would change.
+	// Amounts are stored in minor units, so changing it would change the value of every balance in that currency.
+	ErrExponentChange = errors.New("the exponent of a registered currency cannot change")
+)
+
+// Currency is an entry of the currency registry
+type Currency struct {
+	Code     model.CurrencyType `json:"code"`
+	Exponent int32              `json:"exponent"`
+	Symbol   string             `json:"symbol"`
+	Enabled  bool               `json:"enabled"`
+}
+
+// Validate checks that the code is a well-formed ISO 4217 code and that the exponent is in range
+func (currency Currency) Validate() error {
+	if !currency.Code.IsValid() {
+		return fmt.Errorf("%w: code %q is not an ISO 4217 code", ErrInvalidCurrency, currency.Code)
+	}
+	if currency.Exponent < 0 || currency.Exponent > MaxExponent {
+		return fmt.Errorf("%w: exponent %d of %s is out of range", ErrInvalidCurrency, currency.Exponent, currency.Code)
+	}
+	return nil
+}
+
+// DefaultCurrencies is the currency table used when no currency file is configured, the migrations seed the same table
+var DefaultCurrencies = []Currency{
+	{Code: model.USD, Exponent: 2, Symbol: "$", Enabled: true},
+	{Code: model.EUR, Exponent: 2, Symbol: "€", Enabled: true},
+	{Code: model.CAD, Exponent: 2, Symbol: "CA$", Enabled: true},
+}
+
+// Registry holds the currencies known to the system.
+// Lookups are served from memory, so they can be used in request validation.
+type Registry interface {
+	// Lookup returns the currency with the given code
+	Lookup(code model.CurrencyType) (Currency, bool)
+
+	// List returns all currencies ordered by code
+	List() []Currency
+
+	// Put creates a currency or updates its symbol and enabled flag
+	Put(ctx context.Context, currency Currency) (Currency, error)
+}
+
+// IsSupported checks if the currency is registered and enabled
+func IsSupported(registry Registry, code model.CurrencyType) bool {
+	currency, ok := registry.Lookup(code)
+	return ok && currency.Enabled
+}
+
+// MemoryRegistry is a Registry that keeps the currencies in memory.
+// Changes are lost on restart and are not shared between server instances.
+type MemoryRegistry struct {
+	mu         sync.RWMutex
+	currencies map[model.CurrencyType]Currency
+}
+
+// NewMemoryRegistry creates a MemoryRegistry holding the given currencies
+func NewMemoryRegistry(currencies []Currency) (*MemoryRegistry, error) {
+	registry := &MemoryRegistry{currencies: make(map[model.CurrencyType]Currency, len(currencies))}
+	if err := registry.replace(currencies); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// NewFileRegistry creates a MemoryRegistry from a JSON file holding a list of currencies
+func NewFileRegistry(path string) (*MemoryRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read currency file: %w", err)
+	}
+
+	var currencies []Currency
+	if err := json.Unmarshal(data, &currencies); err != nil {
+		return nil, fmt.Errorf("cannot parse currency file: %w", err)
+	}
+	return NewMemoryRegistry(currencies)
+}
+
+// Lookup returns the currency with the given code
+func (registry *MemoryRegistry) Lookup(code model.CurrencyType) (Currency, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	currency, ok := registry.currencies[code]
+	return currency, ok
+}
+
+// List returns all currencies ordered by code
+func (registry *MemoryRegistry) List() []Currency {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	currencies := make([]Currency, 0, len(registry.currencies))
+	for _, currency := range registry.currencies {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+	return currencies
+}
+
+// Put creates a currency or updates its symbol and enabled flag
+func (registry *MemoryRegistry) Put(_ context.Context, currency Currency) (Currency, error) {
+	if err := currency.Validate(); err != nil {
+		return Currency{}, err
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if existing, ok := registry.currencies[currency.Code]; ok && existing.Exponent != currency.Exponent {
+		return Currency{}, fmt.Errorf("%w: %s has exponent %d", ErrExponentChange, currency.Code, existing.Exponent)
+	}
+	registry.currencies[currency.Code] = currency
+	return currency, nil
+}
+
+// replace swaps the whole table. The previous table is kept if any currency is invalid.
+func (registry *MemoryRegistry) replace(currencies []Currency) error {
+	table := make(map[model.CurrencyType]Currency, len(currencies))
+	for _, currency := range currencies {
+		if err := currency.Validate(); err != nil {
+			return err
+		}
+		if _, ok := table[currency.Code]; ok {
+			return fmt.Errorf("%w: %s is listed twice", ErrInvalidCurrency, currency.Code)
+		}
+		table[currency.Code] = currency
+	}
+
+	registry.mu.Lock()
+	registry.currencies = table
+	registry.mu.Unlock()
+	return nil
+}
+
+// PostgresRegistry is a Registry backed by the currencies table.
+// The table is cached in memory, changes made by other server instances are picked up by Reload.
+type PostgresRegistry struct {
+	querier db.Querier
+	cache   *MemoryRegistry
+}
+
+// NewPostgresRegistry creates a PostgresRegistry and loads the currencies table
+func NewPostgresRegistry(ctx context.Context, querier db.Querier) (*PostgresRegistry, error) {
+	registry := &PostgresRegistry{
+		querier: querier,
+		cache:   &MemoryRegistry{currencies: map[model.CurrencyType]Currency{}},
+	}
+	if err := registry.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Reload reads the currencies table again
+func (registry *PostgresRegistry) Reload(ctx context.Context) error {
+	rows, err := registry.querier.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot load currencies: %w", err)
+	}
+
+	currencies := make([]Currency, len(rows))
+	for i, row := range rows {
+		currencies[i] = newCurrency(row)
+	}
+	return registry.cache.replace(currencies)
+}
+
+// Lookup returns the currency with the given code
+func (registry *PostgresRegistry) Lookup(code model.CurrencyType) (Currency, bool) {
+	return registry.cache.Lookup(code)
+}
+
+// List returns all currencies ordered by code
+func (registry *PostgresRegistry) List() []Currency {
+	return registry.cache.List()
+}
+
+// Put creates a currency or updates its symbol and enabled flag, the table refuses to change the exponent
+func (registry *PostgresRegistry) Put(ctx context.Context, currency Currency) (Currency, error) {
+	if err := currency.Validate(); err != nil {
+		return Currency{}, err
+	}
+
+	row, err := registry.querier.UpsertCurrency(ctx, db.UpsertCurrencyParams{
+		Code:     string(currency.Code),
+		Exponent: currency.Exponent,
+		Symbol:   currency.Symbol,
+		Enabled:  currency.Enabled,
+	})
+	if err != nil {
+		// The upsert skips the update, and returns no row, when the exponent differs
+		if errors.Is(err, sql.ErrNoRows) {
+			return Currency{}, fmt.Errorf("%w: %s", ErrExponentChange, currency.Code)
+		}
+		return Currency{}, err
+	}
+
+	currency = newCurrency(row)
+	registry.cache.mu.Lock()
+	registry.cache.currencies[currency.Code] = currency
+	registry.cache.mu.Unlock()
+	return currency, nil
+}
+
+// newCurrency converts a row of the currencies table
+func newCurrency(row db.Currency) Currency {
+	return Currency{
+		Code:     model.CurrencyType(row.Code),
+		Exponent: row.Exponent,
+		Symbol:   row.Symbol,
+		Enabled:  row.Enabled,
+	}
+}
@@ -0,0 +1,120 @@
+package currency
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCurrency(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Unit Test for the currency registry")
+}
+
+var _ = Describe("Registry", func() {
+	It("Test default currencies", func() {
+		registry, err := NewMemoryRegistry(DefaultCurrencies)
+		Expect(err).To(BeNil())
+
+		usd, ok := registry.Lookup(model.USD)
+		Expect(ok).To(BeTrue())
+		Expect(usd.Exponent).To(Equal(int32(2)))
+		Expect(usd.Symbol).To(Equal("$"))
+		Expect(IsSupported(registry, model.USD)).To(BeTrue())
+		Expect(IsSupported(registry, "JPY")).To(BeFalse())
+
+		codes := []model.CurrencyType{}
+		for _, currency := range registry.List() {
+			codes = append(codes, currency.Code)
+		}
+		Expect(codes).To(Equal([]model.CurrencyType{model.CAD, model.EUR, model.USD}))
+	})
+
+	It("Test invalid currencies are rejected", func() {
+		_, err := NewMemoryRegistry([]Currency{{Code: "usd", Exponent: 2}})
+		Expect(err).To(MatchError(ErrInvalidCurrency))
+
+		_, err = NewMemoryRegistry([]Currency{{Code: "USD", Exponent: 5}})
+		Expect(err).To(MatchError(ErrInvalidCurrency))
+
+		_, err = NewMemoryRegistry([]Currency{{Code: "USD", Exponent: 2}, {Code: "USD", Exponent: 2}})
+		Expect(err).To(MatchError(ErrInvalidCurrency))
+	})
+
+	It("Test put adds, disables and keeps the exponent", func() {
+		registry, err := NewMemoryRegistry(DefaultCurrencies)
+		Expect(err).To(BeNil())
+
+		_, err = registry.Put(context.Background(), Currency{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true})
+		Expect(err).To(BeNil())
+		Expect(IsSupported(registry, "JPY")).To(BeTrue())
+
+		_, err = registry.Put(context.Background(), Currency{Code: model.CAD, Exponent: 2, Symbol: "CA$"})
+		Expect(err).To(BeNil())
+		_, ok := registry.Lookup(model.CAD)
+		Expect(ok).To(BeTrue())
+		Expect(IsSupported(registry, model.CAD)).To(BeFalse())
+
+		_, err = registry.Put(context.Background(), Currency{Code: model.USD, Exponent: 3, Enabled: true})
+		Expect(err).To(MatchError(ErrExponentChange))
+	})
+
+	It("Test file registry", func() {
+		dir, err := os.MkdirTemp("", "currency")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "currencies.json")
+		err = os.WriteFile(path, []byte(`[{"code": "GBP", "exponent": 2, "symbol": "£", "enabled": true}]`), 0o600)
+		Expect(err).To(BeNil())
+
+		registry, err := NewFileRegistry(path)
+		Expect(err).To(BeNil())
+		Expect(IsSupported(registry, "GBP")).To(BeTrue())
+		Expect(IsSupported(registry, model.USD)).To(BeFalse())
+
+		err = os.WriteFile(path, []byte(`not json`), 0o600)
+		Expect(err).To(BeNil())
+		_, err = NewFileRegistry(path)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("Test postgres registry", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			ListCurrencies(gomock.Any()).
+			Times(1).
+			Return([]db.Currency{{Code: "USD", Exponent: 2, Symbol: "$", Enabled: true}}, nil)
+
+		registry, err := NewPostgresRegistry(context.Background(), store)
+		Expect(err).To(BeNil())
+		Expect(IsSupported(registry, model.USD)).To(BeTrue())
+
+		store.EXPECT().
+			UpsertCurrency(gomock.Any(), gomock.Eq(db.UpsertCurrencyParams{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true})).
+			Times(1).
+			Return(db.Currency{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true}, nil)
+		_, err = registry.Put(context.Background(), Currency{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true})
+		Expect(err).To(BeNil())
+		Expect(IsSupported(registry, "JPY")).To(BeTrue())
+
+		store.EXPECT().
+			UpsertCurrency(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(db.Currency{}, sql.ErrNoRows)
+		_, err = registry.Put(context.Background(), Currency{Code: model.USD, Exponent: 3, Enabled: true})
+		Expect(err).To(MatchError(ErrExponentChange))
+	})
+})
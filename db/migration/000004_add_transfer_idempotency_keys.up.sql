@@ -0,0 +1,13 @@
+CREATE TABLE "transfer_idempotency_keys" (
+                                             "key" varchar PRIMARY KEY,
+                                             "request_hash" varchar NOT NULL,
+                                             "transfer_id" bigint NOT NULL,
+                                             "result" jsonb NOT NULL,
+                                             "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "transfer_idempotency_keys"."request_hash" IS 'hash of the transfer parameters the key was first used with';
+
+COMMENT ON COLUMN "transfer_idempotency_keys"."result" IS 'transfer result returned to replays of the key';
+
+ALTER TABLE "transfer_idempotency_keys" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "exchange_rate";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "to_amount";
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be postive';
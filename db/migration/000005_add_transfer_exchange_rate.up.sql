@@ -0,0 +1,13 @@
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+
+UPDATE "transfers" SET "to_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric(20, 10) NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be postive, in the currency of the source account';
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited, in the currency of the destination account';
+
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'rate applied to amount to get to_amount';
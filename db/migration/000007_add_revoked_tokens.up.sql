@@ -0,0 +1,9 @@
+CREATE TABLE "revoked_tokens" (
+                                  "id" uuid PRIMARY KEY,
+                                  "expires_at" timestamptz NOT NULL,
+                                  "revoked_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "revoked_tokens" ("expires_at");
+
+COMMENT ON COLUMN "revoked_tokens"."id" IS 'ID of the token payload';
@@ -0,0 +1,5 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('customer', 'teller', 'admin'));
+
+COMMENT ON COLUMN "users"."role" IS 'customer, teller or admin';
@@ -0,0 +1,3 @@
+ALTER TABLE "accounts" ADD COLUMN "closed_at" timestamptz;
+
+COMMENT ON COLUMN "accounts"."closed_at" IS 'set when the account is closed, closed accounts keep their history';
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "account_status_changes";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_status_check";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "block_credits";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,28 @@
+ALTER TABLE "accounts" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+ALTER TABLE "accounts" ADD COLUMN "block_credits" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_status_check" CHECK ("status" IN ('active', 'frozen', 'closed'));
+
+UPDATE "accounts" SET "status" = 'closed' WHERE "closed_at" IS NOT NULL;
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen or closed, frozen accounts cannot be debited';
+
+COMMENT ON COLUMN "accounts"."block_credits" IS 'frozen accounts cannot be credited either when set';
+
+CREATE TABLE "account_status_changes" (
+                                          "id" bigserial PRIMARY KEY,
+                                          "account_id" bigint NOT NULL,
+                                          "from_status" varchar NOT NULL,
+                                          "to_status" varchar NOT NULL,
+                                          "block_credits" boolean NOT NULL,
+                                          "reason" varchar NOT NULL,
+                                          "changed_by" varchar NOT NULL,
+                                          "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_status_changes" ("account_id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("changed_by") REFERENCES "users" ("username");
@@ -0,0 +1,28 @@
+CREATE TABLE "holds" (
+                         "id" bigserial PRIMARY KEY,
+                         "account_id" bigint NOT NULL,
+                         "to_account_id" bigint NOT NULL,
+                         "amount" bigint NOT NULL,
+                         "status" varchar NOT NULL DEFAULT 'pending',
+                         "transfer_id" bigint,
+                         "expires_at" timestamptz NOT NULL,
+                         "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "holds" ("account_id", "status");
+
+CREATE INDEX ON "holds" ("status", "expires_at");
+
+ALTER TABLE "holds" ADD CONSTRAINT "holds_amount_positive" CHECK ("amount" > 0);
+
+ALTER TABLE "holds" ADD CONSTRAINT "holds_status_check" CHECK ("status" IN ('pending', 'captured', 'released'));
+
+COMMENT ON COLUMN "holds"."amount" IS 'reserved amount, must be positive';
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'set when the hold is captured';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,61 @@
+CREATE TABLE "scheduled_transfers" (
+                                       "id" bigserial PRIMARY KEY,
+                                       "owner" varchar NOT NULL,
+                                       "from_account_id" bigint NOT NULL,
+                                       "to_account_id" bigint NOT NULL,
+                                       "amount" bigint NOT NULL,
+                                       "frequency" varchar NOT NULL,
+                                       "start_at" timestamptz NOT NULL,
+                                       "end_at" timestamptz,
+                                       "next_run_at" timestamptz NOT NULL,
+                                       "retry_at" timestamptz,
+                                       "attempts" int NOT NULL DEFAULT 0,
+                                       "occurrences" int NOT NULL DEFAULT 0,
+                                       "status" varchar NOT NULL DEFAULT 'active',
+                                       "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+CREATE INDEX ON "scheduled_transfers" ("status", "next_run_at");
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfers_amount_positive" CHECK ("amount" > 0);
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfers_frequency_check" CHECK ("frequency" IN ('once', 'daily', 'weekly', 'monthly'));
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfers_status_check" CHECK ("status" IN ('active', 'paused', 'completed', 'cancelled'));
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'time of the next occurrence of the schedule';
+
+COMMENT ON COLUMN "scheduled_transfers"."retry_at" IS 'set while a failed occurrence waits for a retry';
+
+COMMENT ON COLUMN "scheduled_transfers"."attempts" IS 'failed attempts of the next occurrence';
+
+COMMENT ON COLUMN "scheduled_transfers"."occurrences" IS 'number of the next occurrence, counted from start_at';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+CREATE TABLE "scheduled_transfer_runs" (
+                                           "id" bigserial PRIMARY KEY,
+                                           "scheduled_transfer_id" bigint NOT NULL,
+                                           "scheduled_for" timestamptz NOT NULL,
+                                           "attempt" int NOT NULL,
+                                           "outcome" varchar NOT NULL,
+                                           "transfer_id" bigint,
+                                           "failure_reason" varchar NOT NULL DEFAULT '',
+                                           "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD CONSTRAINT "scheduled_transfer_runs_outcome_check" CHECK ("outcome" IN ('succeeded', 'skipped', 'failed'));
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."transfer_id" IS 'set when the run succeeded';
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,7 @@
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint;
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'set on a reversal, the transfer it refunds';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
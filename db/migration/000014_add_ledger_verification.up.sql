@@ -0,0 +1,30 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+CREATE TABLE "ledger_checkpoints" (
+                                      "id" bigserial PRIMARY KEY,
+                                      "last_account_id" bigint NOT NULL,
+                                      "last_entry_id" bigint NOT NULL,
+                                      "last_transfer_id" bigint NOT NULL,
+                                      "account_discrepancies" int NOT NULL,
+                                      "transfer_discrepancies" int NOT NULL,
+                                      "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'the transfer that wrote the entry';
+
+COMMENT ON COLUMN "ledger_checkpoints"."last_entry_id" IS 'entries up to this id have been verified';
+
+COMMENT ON COLUMN "ledger_checkpoints"."last_transfer_id" IS 'transfers up to this id have been verified';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+-- A transfer and its entries are written in the same transaction, so they share the transaction timestamp
+UPDATE "entries" AS e
+SET "transfer_id" = t."id"
+FROM "transfers" AS t
+WHERE e."transfer_id" IS NULL
+  AND e."created_at" = t."created_at"
+  AND ((e."account_id" = t."from_account_id" AND e."amount" = -t."amount")
+    OR (e."account_id" = t."to_account_id" AND e."amount" = t."to_amount"));
@@ -0,0 +1,23 @@
+ALTER TABLE "transfers" ADD COLUMN "description" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "external_reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE "entries" ADD COLUMN "description" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entries" ADD COLUMN "external_reference" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "transfers" ("external_reference");
+
+CREATE INDEX ON "transfers" USING GIN ("metadata");
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_metadata_object" CHECK (jsonb_typeof("metadata") = 'object');
+
+COMMENT ON COLUMN "transfers"."description" IS 'free text memo set by the payer';
+
+COMMENT ON COLUMN "transfers"."external_reference" IS 'reference of the payment in the system of the payer, e.g. an invoice number';
+
+COMMENT ON COLUMN "entries"."description" IS 'copied from the transfer that wrote the entry';
+
+COMMENT ON COLUMN "entries"."external_reference" IS 'copied from the transfer that wrote the entry';
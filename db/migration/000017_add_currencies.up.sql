@@ -0,0 +1,25 @@
+CREATE TABLE "currencies" (
+                              "code" varchar(3) PRIMARY KEY,
+                              "exponent" int NOT NULL DEFAULT 2,
+                              "symbol" varchar NOT NULL DEFAULT '',
+                              "enabled" boolean NOT NULL DEFAULT true,
+                              "created_at" timestamptz NOT NULL DEFAULT (now()),
+                              "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currencies_code_check" CHECK ("code" ~ '^[A-Z]{3}$');
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currencies_exponent_check" CHECK ("exponent" BETWEEN 0 AND 4);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+
+COMMENT ON COLUMN "currencies"."exponent" IS 'digits of the minor unit, amounts are stored in minor units';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'disabled currencies are kept for existing accounts but cannot be used in new requests';
+
+INSERT INTO "currencies" ("code", "exponent", "symbol") VALUES
+    ('USD', 2, '$'),
+    ('EUR', 2, '€'),
+    ('CAD', 2, 'CA$');
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_currency_fkey" FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
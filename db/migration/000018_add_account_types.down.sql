@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS "accounts_owner_currency_idx";
+
+DROP INDEX IF EXISTS "accounts_owner_nickname_key";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "nickname";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "type";
+
+-- Fails if an owner has opened several accounts in the same currency, they must be merged or closed first
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
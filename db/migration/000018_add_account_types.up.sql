@@ -0,0 +1,15 @@
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+ALTER TABLE "accounts" ADD COLUMN "type" varchar NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts" ADD COLUMN "nickname" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_type_check" CHECK ("type" IN ('checking', 'savings', 'loan'));
+
+CREATE UNIQUE INDEX "accounts_owner_nickname_key" ON "accounts" ("owner", "nickname") WHERE "nickname" <> '';
+
+CREATE INDEX ON "accounts" ("owner", "currency");
+
+COMMENT ON COLUMN "accounts"."type" IS 'checking, savings or loan';
+
+COMMENT ON COLUMN "accounts"."nickname" IS 'optional, unique among the accounts of the owner';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "interest_postings";
+
+DROP TABLE IF EXISTS "interest_accruals";
@@ -0,0 +1,48 @@
+CREATE TABLE "interest_accruals" (
+                                     "id" bigserial PRIMARY KEY,
+                                     "account_id" bigint NOT NULL,
+                                     "accrual_date" date NOT NULL,
+                                     "balance" bigint NOT NULL,
+                                     "annual_rate" varchar NOT NULL,
+                                     "amount" bigint NOT NULL,
+                                     "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("accrual_date");
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end of day balance the interest accrued on';
+
+COMMENT ON COLUMN "interest_accruals"."annual_rate" IS 'annual rate of the account type on the accrual date';
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'in millionths of the minor unit, rounded half to even';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE TABLE "interest_postings" (
+                                     "id" bigserial PRIMARY KEY,
+                                     "account_id" bigint NOT NULL,
+                                     "period" date NOT NULL,
+                                     "accrued" bigint NOT NULL,
+                                     "amount" bigint NOT NULL,
+                                     "carry" bigint NOT NULL,
+                                     "entry_id" bigint,
+                                     "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "interest_postings" ("account_id", "period");
+
+COMMENT ON COLUMN "interest_postings"."period" IS 'first day of the month the posted interest accrued in';
+
+COMMENT ON COLUMN "interest_postings"."accrued" IS 'accruals of the period plus the previous carry, in millionths of the minor unit';
+
+COMMENT ON COLUMN "interest_postings"."amount" IS 'posted in minor units, truncated toward zero';
+
+COMMENT ON COLUMN "interest_postings"."carry" IS 'remainder carried to the next posting, in millionths of the minor unit';
+
+COMMENT ON COLUMN "interest_postings"."entry_id" IS 'set when a whole minor unit was posted';
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
@@ -0,0 +1,8 @@
+ALTER TABLE IF EXISTS "transfer_idempotency_keys" DROP CONSTRAINT IF EXISTS "transfer_idempotency_keys_from_account_id_fkey";
+
+ALTER TABLE IF EXISTS "transfer_idempotency_keys" DROP CONSTRAINT IF EXISTS "transfer_idempotency_keys_pkey";
+
+-- Fails if the same key was used from several accounts, the duplicates must be deleted first
+ALTER TABLE "transfer_idempotency_keys" ADD PRIMARY KEY ("key");
+
+ALTER TABLE IF EXISTS "transfer_idempotency_keys" DROP COLUMN IF EXISTS "from_account_id";
@@ -0,0 +1,16 @@
+ALTER TABLE "transfer_idempotency_keys" ADD COLUMN "from_account_id" bigint;
+
+UPDATE "transfer_idempotency_keys"
+SET "from_account_id" = "transfers"."from_account_id"
+FROM "transfers"
+WHERE "transfers"."id" = "transfer_idempotency_keys"."transfer_id";
+
+ALTER TABLE "transfer_idempotency_keys" ALTER COLUMN "from_account_id" SET NOT NULL;
+
+ALTER TABLE "transfer_idempotency_keys" DROP CONSTRAINT "transfer_idempotency_keys_pkey";
+
+ALTER TABLE "transfer_idempotency_keys" ADD PRIMARY KEY ("from_account_id", "key");
+
+COMMENT ON COLUMN "transfer_idempotency_keys"."from_account_id" IS 'keys are scoped to the source account, so clients of different owners cannot collide';
+
+ALTER TABLE "transfer_idempotency_keys" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
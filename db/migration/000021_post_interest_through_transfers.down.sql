@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "interest_accrual_runs";
+
+ALTER TABLE "interest_postings" DROP COLUMN IF EXISTS "transfer_id";
+
+DROP TABLE IF EXISTS "interest_expense_accounts";
+
+-- The interest expense accounts and their owner are kept, the interest transfers that moved their balances still point to them
@@ -0,0 +1,30 @@
+-- The owner of the bank's own accounts, usernames with a dot cannot be registered and an empty hash never matches a password
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+VALUES ('bank.interest', '', 'Bank interest expense', 'interest@bank.invalid');
+
+CREATE TABLE "interest_expense_accounts" (
+                                             "currency" varchar PRIMARY KEY,
+                                             "account_id" bigint UNIQUE NOT NULL,
+                                             "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "interest_expense_accounts"."account_id" IS 'account of the bank that pays and receives the interest in the currency';
+
+ALTER TABLE "interest_expense_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD COLUMN "transfer_id" bigint;
+
+COMMENT ON COLUMN "interest_postings"."transfer_id" IS 'set when a whole minor unit was posted, the transfer from or to the interest expense account';
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE TABLE "interest_accrual_runs" (
+                                         "accrual_date" date PRIMARY KEY,
+                                         "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "interest_accrual_runs"."accrual_date" IS 'day whose interest was accrued for every account, accruers resume after the latest one';
+
+-- Days accrued before the runs were recorded
+INSERT INTO "interest_accrual_runs" ("accrual_date")
+SELECT DISTINCT "accrual_date" FROM "interest_accruals";
@@ -6,9 +6,12 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -35,6 +38,21 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AccountStatementTx mocks base method.
+func (m *MockStore) AccountStatementTx(arg0 context.Context, arg1 db.AccountStatementTxParams) (db.AccountStatementTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountStatementTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountStatementTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountStatementTx indicates an expected call of AccountStatementTx.
+func (mr *MockStoreMockRecorder) AccountStatementTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountStatementTx", reflect.TypeOf((*MockStore)(nil).AccountStatementTx), arg0, arg1)
+}
+
 // AddAccountBalance mocks base method.
 func (m *MockStore) AddAccountBalance(arg0 context.Context, arg1 db.AddAccountBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +68,81 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
+// BatchTransferTx mocks base method.
+func (m *MockStore) BatchTransferTx(arg0 context.Context, arg1 db.BatchTransferTxParams) (db.BatchTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BatchTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchTransferTx indicates an expected call of BatchTransferTx.
+func (mr *MockStoreMockRecorder) BatchTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchTransferTx", reflect.TypeOf((*MockStore)(nil).BatchTransferTx), arg0, arg1)
+}
+
+// BlockSession mocks base method.
+func (m *MockStore) BlockSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSession indicates an expected call of BlockSession.
+func (mr *MockStoreMockRecorder) BlockSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockStore)(nil).BlockSession), arg0, arg1)
+}
+
+// CaptureHold mocks base method.
+func (m *MockStore) CaptureHold(arg0 context.Context, arg1 db.CaptureHoldParams) (db.CaptureHoldResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", arg0, arg1)
+	ret0, _ := ret[0].(db.CaptureHoldResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockStoreMockRecorder) CaptureHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockStore)(nil).CaptureHold), arg0, arg1)
+}
+
+// ChangeAccountStatusTx mocks base method.
+func (m *MockStore) ChangeAccountStatusTx(arg0 context.Context, arg1 db.ChangeAccountStatusTxParams) (db.ChangeAccountStatusTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeAccountStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ChangeAccountStatusTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeAccountStatusTx indicates an expected call of ChangeAccountStatusTx.
+func (mr *MockStoreMockRecorder) ChangeAccountStatusTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeAccountStatusTx", reflect.TypeOf((*MockStore)(nil).ChangeAccountStatusTx), arg0, arg1)
+}
+
+// CountAccounts mocks base method.
+func (m *MockStore) CountAccounts(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAccounts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAccounts indicates an expected call of CountAccounts.
+func (mr *MockStoreMockRecorder) CountAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccounts", reflect.TypeOf((*MockStore)(nil).CountAccounts), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +158,21 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateAccountStatusChange mocks base method.
+func (m *MockStore) CreateAccountStatusChange(arg0 context.Context, arg1 db.CreateAccountStatusChangeParams) (db.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountStatusChange", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountStatusChange indicates an expected call of CreateAccountStatusChange.
+func (mr *MockStoreMockRecorder) CreateAccountStatusChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountStatusChange", reflect.TypeOf((*MockStore)(nil).CreateAccountStatusChange), arg0, arg1)
+}
+
 // CreateEntry mocks base method.
 func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +188,155 @@ func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
+// CreateHold mocks base method.
+func (m *MockStore) CreateHold(arg0 context.Context, arg1 db.CreateHoldParams) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockStoreMockRecorder) CreateHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockStore)(nil).CreateHold), arg0, arg1)
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockStore) CreateIdempotencyKey(arg0 context.Context, arg1 db.CreateIdempotencyKeyParams) (db.TransferIdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferIdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockStoreMockRecorder) CreateIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CreateIdempotencyKey), arg0, arg1)
+}
+
+// CreateInterestAccrual mocks base method.
+func (m *MockStore) CreateInterestAccrual(arg0 context.Context, arg1 db.CreateInterestAccrualParams) (db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestAccrual", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestAccrual indicates an expected call of CreateInterestAccrual.
+func (mr *MockStoreMockRecorder) CreateInterestAccrual(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestAccrual", reflect.TypeOf((*MockStore)(nil).CreateInterestAccrual), arg0, arg1)
+}
+
+// CreateInterestAccrualRun mocks base method.
+func (m *MockStore) CreateInterestAccrualRun(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestAccrualRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInterestAccrualRun indicates an expected call of CreateInterestAccrualRun.
+func (mr *MockStoreMockRecorder) CreateInterestAccrualRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestAccrualRun", reflect.TypeOf((*MockStore)(nil).CreateInterestAccrualRun), arg0, arg1)
+}
+
+// CreateInterestExpenseAccount mocks base method.
+func (m *MockStore) CreateInterestExpenseAccount(arg0 context.Context, arg1 db.CreateInterestExpenseAccountParams) (db.InterestExpenseAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestExpenseAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestExpenseAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestExpenseAccount indicates an expected call of CreateInterestExpenseAccount.
+func (mr *MockStoreMockRecorder) CreateInterestExpenseAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestExpenseAccount", reflect.TypeOf((*MockStore)(nil).CreateInterestExpenseAccount), arg0, arg1)
+}
+
+// CreateInterestPosting mocks base method.
+func (m *MockStore) CreateInterestPosting(arg0 context.Context, arg1 db.CreateInterestPostingParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestPosting indicates an expected call of CreateInterestPosting.
+func (mr *MockStoreMockRecorder) CreateInterestPosting(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestPosting", reflect.TypeOf((*MockStore)(nil).CreateInterestPosting), arg0, arg1)
+}
+
+// CreateLedgerCheckpoint mocks base method.
+func (m *MockStore) CreateLedgerCheckpoint(arg0 context.Context, arg1 db.CreateLedgerCheckpointParams) (db.LedgerCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLedgerCheckpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLedgerCheckpoint indicates an expected call of CreateLedgerCheckpoint.
+func (mr *MockStoreMockRecorder) CreateLedgerCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLedgerCheckpoint", reflect.TypeOf((*MockStore)(nil).CreateLedgerCheckpoint), arg0, arg1)
+}
+
+// CreateScheduledTransfer mocks base method.
+func (m *MockStore) CreateScheduledTransfer(arg0 context.Context, arg1 db.CreateScheduledTransferParams) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransfer indicates an expected call of CreateScheduledTransfer.
+func (mr *MockStoreMockRecorder) CreateScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransfer", reflect.TypeOf((*MockStore)(nil).CreateScheduledTransfer), arg0, arg1)
+}
+
+// CreateScheduledTransferRun mocks base method.
+func (m *MockStore) CreateScheduledTransferRun(arg0 context.Context, arg1 db.CreateScheduledTransferRunParams) (db.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransferRun", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransferRun indicates an expected call of CreateScheduledTransferRun.
+func (mr *MockStoreMockRecorder) CreateScheduledTransferRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransferRun", reflect.TypeOf((*MockStore)(nil).CreateScheduledTransferRun), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
 // CreateTransfer mocks base method.
 func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +381,51 @@ func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
 }
 
+// DeleteExpiredRevokedTokens mocks base method.
+func (m *MockStore) DeleteExpiredRevokedTokens(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRevokedTokens", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredRevokedTokens indicates an expected call of DeleteExpiredRevokedTokens.
+func (mr *MockStoreMockRecorder) DeleteExpiredRevokedTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRevokedTokens", reflect.TypeOf((*MockStore)(nil).DeleteExpiredRevokedTokens), arg0)
+}
+
+// ExecuteScheduledTransferTx mocks base method.
+func (m *MockStore) ExecuteScheduledTransferTx(arg0 context.Context, arg1 db.ExecuteScheduledTransferTxParams) (db.ExecuteScheduledTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteScheduledTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ExecuteScheduledTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteScheduledTransferTx indicates an expected call of ExecuteScheduledTransferTx.
+func (mr *MockStoreMockRecorder) ExecuteScheduledTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScheduledTransferTx", reflect.TypeOf((*MockStore)(nil).ExecuteScheduledTransferTx), arg0, arg1)
+}
+
+// FXTransferTx mocks base method.
+func (m *MockStore) FXTransferTx(arg0 context.Context, arg1 db.FXTransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FXTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FXTransferTx indicates an expected call of FXTransferTx.
+func (mr *MockStoreMockRecorder) FXTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FXTransferTx", reflect.TypeOf((*MockStore)(nil).FXTransferTx), arg0, arg1)
+}
+
 // GetAccount mocks base method.
 func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -154,6 +456,21 @@ func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
 }
 
+// GetCurrency mocks base method.
+func (m *MockStore) GetCurrency(arg0 context.Context, arg1 string) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrency", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrency indicates an expected call of GetCurrency.
+func (mr *MockStoreMockRecorder) GetCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrency", reflect.TypeOf((*MockStore)(nil).GetCurrency), arg0, arg1)
+}
+
 // GetEntry mocks base method.
 func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -169,107 +486,824 @@ func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// GetHold mocks base method.
+func (m *MockStore) GetHold(arg0 context.Context, arg1 int64) (db.Hold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "GetHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// GetHold indicates an expected call of GetHold.
+func (mr *MockStoreMockRecorder) GetHold(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockStore)(nil).GetHold), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// GetHoldForUpdate mocks base method.
+func (m *MockStore) GetHoldForUpdate(arg0 context.Context, arg1 int64) (db.Hold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "GetHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// GetHoldForUpdate indicates an expected call of GetHoldForUpdate.
+func (mr *MockStoreMockRecorder) GetHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldForUpdate", reflect.TypeOf((*MockStore)(nil).GetHoldForUpdate), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(arg0 context.Context, arg1 db.GetIdempotencyKeyParams) (db.TransferIdempotencyKey, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferIdempotencyKey)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), arg0, arg1)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// GetInterestExpenseAccount mocks base method.
+func (m *MockStore) GetInterestExpenseAccount(arg0 context.Context, arg1 string) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "GetInterestExpenseAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+// GetInterestExpenseAccount indicates an expected call of GetInterestExpenseAccount.
+func (mr *MockStoreMockRecorder) GetInterestExpenseAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestExpenseAccount", reflect.TypeOf((*MockStore)(nil).GetInterestExpenseAccount), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// GetInterestPosting mocks base method.
+func (m *MockStore) GetInterestPosting(arg0 context.Context, arg1 db.GetInterestPostingParams) (db.InterestPosting, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "GetInterestPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+// GetInterestPosting indicates an expected call of GetInterestPosting.
+func (mr *MockStoreMockRecorder) GetInterestPosting(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestPosting", reflect.TypeOf((*MockStore)(nil).GetInterestPosting), arg0, arg1)
 }
 
-// TransferTx mocks base method.
-func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+// GetLatestInterestAccrualRun mocks base method.
+func (m *MockStore) GetLatestInterestAccrualRun(arg0 context.Context) (time.Time, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
-	ret0, _ := ret[0].(db.TransferTxResult)
+	ret := m.ctrl.Call(m, "GetLatestInterestAccrualRun", arg0)
+	ret0, _ := ret[0].(time.Time)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TransferTx indicates an expected call of TransferTx.
-func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+// GetLatestInterestAccrualRun indicates an expected call of GetLatestInterestAccrualRun.
+func (mr *MockStoreMockRecorder) GetLatestInterestAccrualRun(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestInterestAccrualRun", reflect.TypeOf((*MockStore)(nil).GetLatestInterestAccrualRun), arg0)
 }
 
-// UpdateAccount mocks base method.
-func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+// GetLatestInterestPosting mocks base method.
+func (m *MockStore) GetLatestInterestPosting(arg0 context.Context, arg1 db.GetLatestInterestPostingParams) (db.InterestPosting, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "GetLatestInterestPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccount indicates an expected call of UpdateAccount.
-func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+// GetLatestInterestPosting indicates an expected call of GetLatestInterestPosting.
+func (mr *MockStoreMockRecorder) GetLatestInterestPosting(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestInterestPosting", reflect.TypeOf((*MockStore)(nil).GetLatestInterestPosting), arg0, arg1)
+}
+
+// GetLatestLedgerCheckpoint mocks base method.
+func (m *MockStore) GetLatestLedgerCheckpoint(arg0 context.Context) (db.LedgerCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestLedgerCheckpoint", arg0)
+	ret0, _ := ret[0].(db.LedgerCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestLedgerCheckpoint indicates an expected call of GetLatestLedgerCheckpoint.
+func (mr *MockStoreMockRecorder) GetLatestLedgerCheckpoint(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestLedgerCheckpoint", reflect.TypeOf((*MockStore)(nil).GetLatestLedgerCheckpoint), arg0)
+}
+
+// GetLedgerHighWaterMark mocks base method.
+func (m *MockStore) GetLedgerHighWaterMark(arg0 context.Context, arg1 time.Time) (db.GetLedgerHighWaterMarkRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerHighWaterMark", arg0, arg1)
+	ret0, _ := ret[0].(db.GetLedgerHighWaterMarkRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerHighWaterMark indicates an expected call of GetLedgerHighWaterMark.
+func (mr *MockStoreMockRecorder) GetLedgerHighWaterMark(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerHighWaterMark", reflect.TypeOf((*MockStore)(nil).GetLedgerHighWaterMark), arg0, arg1)
+}
+
+// GetScheduledTransfer mocks base method.
+func (m *MockStore) GetScheduledTransfer(arg0 context.Context, arg1 int64) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransfer indicates an expected call of GetScheduledTransfer.
+func (mr *MockStoreMockRecorder) GetScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransfer", reflect.TypeOf((*MockStore)(nil).GetScheduledTransfer), arg0, arg1)
+}
+
+// GetScheduledTransferForUpdate mocks base method.
+func (m *MockStore) GetScheduledTransferForUpdate(arg0 context.Context, arg1 int64) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransferForUpdate indicates an expected call of GetScheduledTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetScheduledTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetScheduledTransferForUpdate), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockStore) IsTokenRevoked(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockStoreMockRecorder) IsTokenRevoked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockStore)(nil).IsTokenRevoked), arg0, arg1)
+}
+
+// ListAccountBalanceMismatches mocks base method.
+func (m *MockStore) ListAccountBalanceMismatches(arg0 context.Context, arg1 db.ListAccountBalanceMismatchesParams) ([]db.ListAccountBalanceMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountBalanceMismatches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAccountBalanceMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountBalanceMismatches indicates an expected call of ListAccountBalanceMismatches.
+func (mr *MockStoreMockRecorder) ListAccountBalanceMismatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountBalanceMismatches", reflect.TypeOf((*MockStore)(nil).ListAccountBalanceMismatches), arg0, arg1)
+}
+
+// ListAccountStatusChanges mocks base method.
+func (m *MockStore) ListAccountStatusChanges(arg0 context.Context, arg1 db.ListAccountStatusChangesParams) ([]db.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountStatusChanges", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountStatusChanges indicates an expected call of ListAccountStatusChanges.
+func (mr *MockStoreMockRecorder) ListAccountStatusChanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountStatusChanges", reflect.TypeOf((*MockStore)(nil).ListAccountStatusChanges), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListAccountsByTypes mocks base method.
+func (m *MockStore) ListAccountsByTypes(arg0 context.Context, arg1 db.ListAccountsByTypesParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsByTypes", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsByTypes indicates an expected call of ListAccountsByTypes.
+func (mr *MockStoreMockRecorder) ListAccountsByTypes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsByTypes", reflect.TypeOf((*MockStore)(nil).ListAccountsByTypes), arg0, arg1)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockStore) ListCurrencies(arg0 context.Context) ([]db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", arg0)
+	ret0, _ := ret[0].([]db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockStoreMockRecorder) ListCurrencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockStore)(nil).ListCurrencies), arg0)
+}
+
+// ListDueScheduledTransfers mocks base method.
+func (m *MockStore) ListDueScheduledTransfers(arg0 context.Context, arg1 db.ListDueScheduledTransfersParams) ([]db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueScheduledTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueScheduledTransfers indicates an expected call of ListDueScheduledTransfers.
+func (mr *MockStoreMockRecorder) ListDueScheduledTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueScheduledTransfers", reflect.TypeOf((*MockStore)(nil).ListDueScheduledTransfers), arg0, arg1)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListEntriesBetween mocks base method.
+func (m *MockStore) ListEntriesBetween(arg0 context.Context, arg1 db.ListEntriesBetweenParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesBetween", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesBetween indicates an expected call of ListEntriesBetween.
+func (mr *MockStoreMockRecorder) ListEntriesBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesBetween", reflect.TypeOf((*MockStore)(nil).ListEntriesBetween), arg0, arg1)
+}
+
+// ListScheduledTransferRuns mocks base method.
+func (m *MockStore) ListScheduledTransferRuns(arg0 context.Context, arg1 db.ListScheduledTransferRunsParams) ([]db.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransferRuns", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransferRuns indicates an expected call of ListScheduledTransferRuns.
+func (mr *MockStoreMockRecorder) ListScheduledTransferRuns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransferRuns", reflect.TypeOf((*MockStore)(nil).ListScheduledTransferRuns), arg0, arg1)
+}
+
+// ListScheduledTransfers mocks base method.
+func (m *MockStore) ListScheduledTransfers(arg0 context.Context, arg1 db.ListScheduledTransfersParams) ([]db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransfers indicates an expected call of ListScheduledTransfers.
+func (mr *MockStoreMockRecorder) ListScheduledTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransfers", reflect.TypeOf((*MockStore)(nil).ListScheduledTransfers), arg0, arg1)
+}
+
+// ListTransferEntryMismatches mocks base method.
+func (m *MockStore) ListTransferEntryMismatches(arg0 context.Context, arg1 db.ListTransferEntryMismatchesParams) ([]db.ListTransferEntryMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferEntryMismatches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListTransferEntryMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferEntryMismatches indicates an expected call of ListTransferEntryMismatches.
+func (mr *MockStoreMockRecorder) ListTransferEntryMismatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferEntryMismatches", reflect.TypeOf((*MockStore)(nil).ListTransferEntryMismatches), arg0, arg1)
+}
+
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+}
+
+// ListUnpostedInterestAccounts mocks base method.
+func (m *MockStore) ListUnpostedInterestAccounts(arg0 context.Context, arg1 db.ListUnpostedInterestAccountsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpostedInterestAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpostedInterestAccounts indicates an expected call of ListUnpostedInterestAccounts.
+func (mr *MockStoreMockRecorder) ListUnpostedInterestAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpostedInterestAccounts", reflect.TypeOf((*MockStore)(nil).ListUnpostedInterestAccounts), arg0, arg1)
+}
+
+// LockIdempotencyKey mocks base method.
+func (m *MockStore) LockIdempotencyKey(arg0 context.Context, arg1 db.LockIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockIdempotencyKey indicates an expected call of LockIdempotencyKey.
+func (mr *MockStoreMockRecorder) LockIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockIdempotencyKey", reflect.TypeOf((*MockStore)(nil).LockIdempotencyKey), arg0, arg1)
+}
+
+// LockInterestExpenseAccount mocks base method.
+func (m *MockStore) LockInterestExpenseAccount(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockInterestExpenseAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockInterestExpenseAccount indicates an expected call of LockInterestExpenseAccount.
+func (mr *MockStoreMockRecorder) LockInterestExpenseAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockInterestExpenseAccount", reflect.TypeOf((*MockStore)(nil).LockInterestExpenseAccount), arg0, arg1)
+}
+
+// MarkHoldCaptured mocks base method.
+func (m *MockStore) MarkHoldCaptured(arg0 context.Context, arg1 db.MarkHoldCapturedParams) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkHoldCaptured", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkHoldCaptured indicates an expected call of MarkHoldCaptured.
+func (mr *MockStoreMockRecorder) MarkHoldCaptured(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkHoldCaptured", reflect.TypeOf((*MockStore)(nil).MarkHoldCaptured), arg0, arg1)
+}
+
+// MarkHoldReleased mocks base method.
+func (m *MockStore) MarkHoldReleased(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkHoldReleased", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkHoldReleased indicates an expected call of MarkHoldReleased.
+func (mr *MockStoreMockRecorder) MarkHoldReleased(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkHoldReleased", reflect.TypeOf((*MockStore)(nil).MarkHoldReleased), arg0, arg1)
+}
+
+// PlaceHold mocks base method.
+func (m *MockStore) PlaceHold(arg0 context.Context, arg1 db.PlaceHoldParams) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockStoreMockRecorder) PlaceHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockStore)(nil).PlaceHold), arg0, arg1)
+}
+
+// PostInterestTx mocks base method.
+func (m *MockStore) PostInterestTx(arg0 context.Context, arg1 db.PostInterestTxParams) (db.PostInterestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostInterestTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PostInterestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostInterestTx indicates an expected call of PostInterestTx.
+func (mr *MockStoreMockRecorder) PostInterestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInterestTx", reflect.TypeOf((*MockStore)(nil).PostInterestTx), arg0, arg1)
+}
+
+// ReleaseExpiredHolds mocks base method.
+func (m *MockStore) ReleaseExpiredHolds(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredHolds", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredHolds indicates an expected call of ReleaseExpiredHolds.
+func (mr *MockStoreMockRecorder) ReleaseExpiredHolds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredHolds", reflect.TypeOf((*MockStore)(nil).ReleaseExpiredHolds), arg0)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockStore) ReleaseHold(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockStoreMockRecorder) ReleaseHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockStore)(nil).ReleaseHold), arg0, arg1)
+}
+
+// ReverseTransferTx mocks base method.
+func (m *MockStore) ReverseTransferTx(arg0 context.Context, arg1 db.ReverseTransferTxParams) (db.ReverseTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ReverseTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransferTx indicates an expected call of ReverseTransferTx.
+func (mr *MockStoreMockRecorder) ReverseTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransferTx", reflect.TypeOf((*MockStore)(nil).ReverseTransferTx), arg0, arg1)
+}
+
+// RevokeToken mocks base method.
+func (m *MockStore) RevokeToken(arg0 context.Context, arg1 db.RevokeTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockStoreMockRecorder) RevokeToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockStore)(nil).RevokeToken), arg0, arg1)
+}
+
+// SearchTransfers mocks base method.
+func (m *MockStore) SearchTransfers(arg0 context.Context, arg1 db.SearchTransfersParams) ([]db.SearchTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransfers indicates an expected call of SearchTransfers.
+func (mr *MockStoreMockRecorder) SearchTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransfers", reflect.TypeOf((*MockStore)(nil).SearchTransfers), arg0, arg1)
+}
+
+// SumActiveHolds mocks base method.
+func (m *MockStore) SumActiveHolds(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumActiveHolds", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumActiveHolds indicates an expected call of SumActiveHolds.
+func (mr *MockStoreMockRecorder) SumActiveHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumActiveHolds", reflect.TypeOf((*MockStore)(nil).SumActiveHolds), arg0, arg1)
+}
+
+// SumActiveHoldsByAccounts mocks base method.
+func (m *MockStore) SumActiveHoldsByAccounts(arg0 context.Context, arg1 []int64) ([]db.SumActiveHoldsByAccountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumActiveHoldsByAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumActiveHoldsByAccountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumActiveHoldsByAccounts indicates an expected call of SumActiveHoldsByAccounts.
+func (mr *MockStoreMockRecorder) SumActiveHoldsByAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumActiveHoldsByAccounts", reflect.TypeOf((*MockStore)(nil).SumActiveHoldsByAccounts), arg0, arg1)
+}
+
+// SumEntriesBefore mocks base method.
+func (m *MockStore) SumEntriesBefore(arg0 context.Context, arg1 db.SumEntriesBeforeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesBefore indicates an expected call of SumEntriesBefore.
+func (mr *MockStoreMockRecorder) SumEntriesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesBefore", reflect.TypeOf((*MockStore)(nil).SumEntriesBefore), arg0, arg1)
+}
+
+// SumEntriesSince mocks base method.
+func (m *MockStore) SumEntriesSince(arg0 context.Context, arg1 db.SumEntriesSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesSince indicates an expected call of SumEntriesSince.
+func (mr *MockStoreMockRecorder) SumEntriesSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesSince", reflect.TypeOf((*MockStore)(nil).SumEntriesSince), arg0, arg1)
+}
+
+// SumInterestAccruals mocks base method.
+func (m *MockStore) SumInterestAccruals(arg0 context.Context, arg1 db.SumInterestAccrualsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumInterestAccruals", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumInterestAccruals indicates an expected call of SumInterestAccruals.
+func (mr *MockStoreMockRecorder) SumInterestAccruals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumInterestAccruals", reflect.TypeOf((*MockStore)(nil).SumInterestAccruals), arg0, arg1)
+}
+
+// SumTransferReversals mocks base method.
+func (m *MockStore) SumTransferReversals(arg0 context.Context, arg1 sql.NullInt64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumTransferReversals", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumTransferReversals indicates an expected call of SumTransferReversals.
+func (mr *MockStoreMockRecorder) SumTransferReversals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumTransferReversals", reflect.TypeOf((*MockStore)(nil).SumTransferReversals), arg0, arg1)
+}
+
+// TransferTx mocks base method.
+func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+}
+
+// UpdateAccount mocks base method.
+func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccount indicates an expected call of UpdateAccount.
+func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+}
+
+// UpdateAccountOverdraftLimit mocks base method.
+func (m *MockStore) UpdateAccountOverdraftLimit(arg0 context.Context, arg1 db.UpdateAccountOverdraftLimitParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountOverdraftLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountOverdraftLimit indicates an expected call of UpdateAccountOverdraftLimit.
+func (mr *MockStoreMockRecorder) UpdateAccountOverdraftLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountOverdraftLimit", reflect.TypeOf((*MockStore)(nil).UpdateAccountOverdraftLimit), arg0, arg1)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockStore) UpdateAccountStatus(arg0 context.Context, arg1 db.UpdateAccountStatusParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockStoreMockRecorder) UpdateAccountStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockStore)(nil).UpdateAccountStatus), arg0, arg1)
+}
+
+// UpdateScheduledTransfer mocks base method.
+func (m *MockStore) UpdateScheduledTransfer(arg0 context.Context, arg1 db.UpdateScheduledTransferParams) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduledTransfer indicates an expected call of UpdateScheduledTransfer.
+func (mr *MockStoreMockRecorder) UpdateScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledTransfer", reflect.TypeOf((*MockStore)(nil).UpdateScheduledTransfer), arg0, arg1)
+}
+
+// UpdateScheduledTransferState mocks base method.
+func (m *MockStore) UpdateScheduledTransferState(arg0 context.Context, arg1 db.UpdateScheduledTransferStateParams) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledTransferState", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduledTransferState indicates an expected call of UpdateScheduledTransferState.
+func (mr *MockStoreMockRecorder) UpdateScheduledTransferState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledTransferState", reflect.TypeOf((*MockStore)(nil).UpdateScheduledTransferState), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// UpsertCurrency mocks base method.
+func (m *MockStore) UpsertCurrency(arg0 context.Context, arg1 db.UpsertCurrencyParams) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCurrency", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCurrency indicates an expected call of UpsertCurrency.
+func (mr *MockStoreMockRecorder) UpsertCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCurrency", reflect.TypeOf((*MockStore)(nil).UpsertCurrency), arg0, arg1)
+}
+
+// VerifyLedger mocks base method.
+func (m *MockStore) VerifyLedger(arg0 context.Context, arg1 db.VerifyLedgerParams) (db.LedgerReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyLedger", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyLedger indicates an expected call of VerifyLedger.
+func (mr *MockStoreMockRecorder) VerifyLedger(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyLedger", reflect.TypeOf((*MockStore)(nil).VerifyLedger), arg0, arg1)
 }
@@ -2,9 +2,11 @@
 INSERT INTO accounts (
     owner,
     balance,
-    currency
+    currency,
+    type,
+    nickname
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetAccount :one
@@ -13,10 +15,27 @@ WHERE id = $1 LIMIT 1;
 
 -- name: ListAccounts :many
 SELECT * FROM accounts
-WHERE owner = $1
+WHERE owner = sqlc.arg(owner)
+  AND (sqlc.narg(after_id)::bigint IS NULL OR id > sqlc.narg(after_id)::bigint)
 ORDER BY id
-LIMIT $2
-OFFSET $3;
+LIMIT sqlc.arg('limit');
+
+-- name: ListAccountsByTypes :many
+SELECT * FROM accounts
+WHERE type = ANY(sqlc.arg(types)::varchar[])
+  AND status <> 'closed'
+  AND created_at < sqlc.arg(created_before)
+  AND id > sqlc.arg(after_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM interest_expense_accounts
+    WHERE interest_expense_accounts.account_id = accounts.id
+)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: CountAccounts :one
+SELECT count(*) FROM accounts
+WHERE owner = $1;
 
 -- name: UpdateAccount :one
 UPDATE accounts
@@ -37,4 +56,18 @@ FOR NO KEY UPDATE;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(status),
+    block_credits = sqlc.arg(block_credits),
+    closed_at = CASE WHEN sqlc.arg(status)::varchar = 'closed' THEN now() ELSE closed_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
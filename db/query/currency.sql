@@ -0,0 +1,22 @@
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY code;
+
+-- name: UpsertCurrency :one
+INSERT INTO currencies (
+    code,
+    exponent,
+    symbol,
+    enabled
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (code) DO UPDATE
+SET symbol = EXCLUDED.symbol,
+    enabled = EXCLUDED.enabled,
+    updated_at = now()
+WHERE currencies.exponent = EXCLUDED.exponent
+RETURNING *;
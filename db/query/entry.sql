@@ -1,9 +1,12 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transfer_id,
+    description,
+    external_reference
 ) VALUES (
-    $1, $2
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -15,4 +18,21 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-    OFFSET $3;
\ No newline at end of file
+    OFFSET $3;
+
+-- name: ListEntriesBetween :many
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+ORDER BY created_at, id;
+
+-- name: SumEntriesBefore :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at < sqlc.arg(before);
+
+-- name: SumEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(since);
@@ -0,0 +1,52 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    to_account_id,
+    amount,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: MarkHoldCaptured :one
+UPDATE holds
+SET status = 'captured',
+    transfer_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkHoldReleased :one
+UPDATE holds
+SET status = 'released'
+WHERE id = $1
+RETURNING *;
+
+-- name: ReleaseExpiredHolds :execrows
+UPDATE holds
+SET status = 'released'
+WHERE status = 'pending'
+  AND expires_at <= now();
+
+-- name: SumActiveHolds :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM holds
+WHERE account_id = $1
+  AND status = 'pending'
+  AND expires_at > now();
+
+-- name: SumActiveHoldsByAccounts :many
+SELECT account_id, COALESCE(SUM(amount), 0)::bigint AS total
+FROM holds
+WHERE account_id = ANY(sqlc.arg(account_ids)::bigint[])
+  AND status = 'pending'
+  AND expires_at > now()
+GROUP BY account_id;
@@ -0,0 +1,19 @@
+-- name: LockIdempotencyKey :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg(from_account_id)::bigint || ':' || sqlc.arg(key)::text));
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM transfer_idempotency_keys
+WHERE from_account_id = $1
+  AND key = $2
+LIMIT 1;
+
+-- name: CreateIdempotencyKey :one
+INSERT INTO transfer_idempotency_keys (
+    key,
+    request_hash,
+    transfer_id,
+    result,
+    from_account_id
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
@@ -0,0 +1,109 @@
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    annual_rate,
+    amount
+)
+SELECT sqlc.arg(account_id)::bigint,
+       sqlc.arg(accrual_date)::date,
+       sqlc.arg(balance)::bigint,
+       sqlc.arg(annual_rate)::varchar,
+       sqlc.arg(amount)::bigint
+WHERE NOT EXISTS (
+    SELECT 1 FROM interest_postings
+    WHERE interest_postings.account_id = sqlc.arg(account_id)::bigint
+      AND interest_postings.period = date_trunc('month', sqlc.arg(accrual_date)::date)::date
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+
+-- name: CreateInterestAccrualRun :exec
+INSERT INTO interest_accrual_runs (
+    accrual_date
+) VALUES (
+    $1
+)
+ON CONFLICT (accrual_date) DO NOTHING;
+
+-- name: CreateInterestExpenseAccount :one
+INSERT INTO interest_expense_accounts (
+    currency,
+    account_id
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+    account_id,
+    period,
+    accrued,
+    amount,
+    carry,
+    entry_id,
+    transfer_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetInterestExpenseAccount :one
+SELECT accounts.* FROM accounts
+JOIN interest_expense_accounts ON interest_expense_accounts.account_id = accounts.id
+WHERE interest_expense_accounts.currency = $1
+LIMIT 1;
+
+-- name: GetInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = $1
+  AND period = $2
+LIMIT 1;
+
+-- name: GetLatestInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = sqlc.arg(account_id)
+  AND period < sqlc.arg(before)
+ORDER BY period DESC
+LIMIT 1;
+
+-- name: GetLatestInterestAccrualRun :one
+SELECT accrual_date FROM interest_accrual_runs
+ORDER BY accrual_date DESC
+LIMIT 1;
+
+-- name: ListUnpostedInterestAccounts :many
+SELECT candidates.account_id FROM (
+    SELECT interest_accruals.account_id FROM interest_accruals
+    WHERE interest_accruals.accrual_date >= sqlc.arg(period_start)
+      AND interest_accruals.accrual_date < sqlc.arg(period_end)
+    UNION
+    -- The carry of the latest posting is posted again even without accruals in the month,
+    -- e.g. the interest held while the account did not accept credits
+    SELECT latest.account_id FROM (
+        SELECT DISTINCT ON (interest_postings.account_id) interest_postings.account_id, interest_postings.carry
+        FROM interest_postings
+        WHERE interest_postings.period < sqlc.arg(period_start)
+        ORDER BY interest_postings.account_id, interest_postings.period DESC
+    ) AS latest
+    JOIN accounts ON accounts.id = latest.account_id
+    WHERE latest.carry <> 0
+      AND accounts.status <> 'closed'
+) AS candidates
+WHERE candidates.account_id > sqlc.arg(after_account_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM interest_postings
+    WHERE interest_postings.account_id = candidates.account_id
+      AND interest_postings.period = sqlc.arg(period_start)
+)
+ORDER BY candidates.account_id
+LIMIT sqlc.arg('limit');
+
+-- name: LockInterestExpenseAccount :exec
+SELECT pg_advisory_xact_lock(hashtext('interest_expense:' || sqlc.arg(currency)::text));
+
+-- name: SumInterestAccruals :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND accrual_date >= sqlc.arg(period_start)
+  AND accrual_date < sqlc.arg(period_end);
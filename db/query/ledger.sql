@@ -0,0 +1,54 @@
+-- name: CreateLedgerCheckpoint :one
+INSERT INTO ledger_checkpoints (
+    last_account_id,
+    last_entry_id,
+    last_transfer_id,
+    account_discrepancies,
+    transfer_discrepancies
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetLatestLedgerCheckpoint :one
+SELECT * FROM ledger_checkpoints
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetLedgerHighWaterMark :one
+SELECT (SELECT COALESCE(MAX(id), 0) FROM accounts WHERE accounts.created_at < sqlc.arg(settled_before))::bigint AS last_account_id,
+       (SELECT COALESCE(MAX(id), 0) FROM entries WHERE entries.created_at < sqlc.arg(settled_before))::bigint AS last_entry_id,
+       (SELECT COALESCE(MAX(id), 0) FROM transfers WHERE transfers.created_at < sqlc.arg(settled_before))::bigint AS last_transfer_id;
+
+-- name: ListAccountBalanceMismatches :many
+SELECT a.id AS account_id,
+       a.balance,
+       COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id <= sqlc.arg(last_account_id)
+  AND (a.id > sqlc.arg(after_account_id)
+    OR a.id IN (SELECT account_id FROM entries
+                WHERE entries.id > sqlc.arg(after_entry_id)
+                  AND entries.id <= sqlc.arg(last_entry_id)))
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListTransferEntryMismatches :many
+SELECT t.id AS transfer_id,
+       t.from_account_id,
+       t.to_account_id,
+       t.amount,
+       t.to_amount,
+       COUNT(e.id)::int AS entry_count,
+       COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id AND e.amount < 0), 0)::bigint AS debited,
+       COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id AND e.amount > 0), 0)::bigint AS credited
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+WHERE t.id > sqlc.arg(after_transfer_id)
+  AND t.id <= sqlc.arg(last_transfer_id)
+GROUP BY t.id
+HAVING COUNT(e.id) <> 2
+    OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id AND e.amount < 0), 0) <> -t.amount
+    OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id AND e.amount > 0), 0) <> t.to_amount
+ORDER BY t.id;
@@ -0,0 +1,17 @@
+-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (
+    id,
+    expires_at
+) VALUES (
+    $1, $2
+) ON CONFLICT (id) DO NOTHING;
+
+-- name: IsTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1 FROM revoked_tokens
+    WHERE id = $1
+) AS revoked;
+
+-- name: DeleteExpiredRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < now();
@@ -0,0 +1,55 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    frequency,
+    start_at,
+    end_at,
+    next_run_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ListDueScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE status = 'active'
+  AND COALESCE(retry_at, next_run_at) <= sqlc.arg(now)
+ORDER BY COALESCE(retry_at, next_run_at)
+LIMIT sqlc.arg(limit_count);
+
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET amount = $2,
+    status = $3,
+    end_at = $4
+WHERE id = $1
+  AND status IN ('active', 'paused')
+RETURNING *;
+
+-- name: UpdateScheduledTransferState :one
+UPDATE scheduled_transfers
+SET status = $2,
+    next_run_at = $3,
+    retry_at = $4,
+    attempts = $5,
+    occurrences = $6
+WHERE id = $1
+RETURNING *;
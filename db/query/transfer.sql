@@ -2,20 +2,63 @@
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    reversal_of,
+    description,
+    external_reference,
+    metadata
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = sqlc.arg(from_account_id) OR
+    to_account_id = sqlc.arg(to_account_id))
+  AND (sqlc.narg(external_reference)::varchar IS NULL OR external_reference = sqlc.narg(external_reference)::varchar)
+  AND (sqlc.narg(search)::varchar IS NULL OR strpos(lower(description), lower(sqlc.narg(search)::varchar)) > 0)
+  AND (sqlc.narg(metadata)::text IS NULL OR metadata @> CAST(sqlc.narg(metadata)::text AS jsonb))
 ORDER BY id
-LIMIT $3
-    OFFSET $4;
\ No newline at end of file
+LIMIT sqlc.arg('limit')
+    OFFSET sqlc.arg('offset');
+
+-- name: SumTransferReversals :one
+SELECT COALESCE(SUM(to_amount), 0)::bigint AS total
+FROM transfers
+WHERE reversal_of = $1;
+
+-- name: SearchTransfers :many
+SELECT sqlc.embed(t), fa.currency AS from_currency, ta.currency AS to_currency FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE ((sqlc.arg(outgoing)::bool
+        AND fa.owner = sqlc.arg(owner)
+        AND (sqlc.narg(account_id)::bigint IS NULL OR fa.id = sqlc.narg(account_id)::bigint)
+        AND (sqlc.narg(currency)::varchar IS NULL OR fa.currency = sqlc.narg(currency)::varchar)
+        AND (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)::bigint)
+        AND (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)::bigint))
+    OR (sqlc.arg(incoming)::bool
+        AND ta.owner = sqlc.arg(owner)
+        AND (sqlc.narg(account_id)::bigint IS NULL OR ta.id = sqlc.narg(account_id)::bigint)
+        AND (sqlc.narg(currency)::varchar IS NULL OR ta.currency = sqlc.narg(currency)::varchar)
+        AND (sqlc.narg(min_amount)::bigint IS NULL OR t.to_amount >= sqlc.narg(min_amount)::bigint)
+        AND (sqlc.narg(max_amount)::bigint IS NULL OR t.to_amount <= sqlc.narg(max_amount)::bigint)))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)::timestamptz)
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)::timestamptz)
+  AND (sqlc.narg(external_reference)::varchar IS NULL OR t.external_reference = sqlc.narg(external_reference)::varchar)
+  AND (sqlc.narg(search)::varchar IS NULL OR strpos(lower(t.description), lower(sqlc.narg(search)::varchar)) > 0)
+  AND (sqlc.narg(before_id)::bigint IS NULL OR t.id < sqlc.narg(before_id)::bigint)
+ORDER BY t.id DESC
+LIMIT sqlc.arg('limit');
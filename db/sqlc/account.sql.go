@@ -5,13 +5,17 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname
 `
 
 type AddAccountBalanceParams struct {
@@ -28,28 +32,56 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.BlockCredits,
+		&i.Type,
+		&i.Nickname,
 	)
 	return i, err
 }
 
+const countAccounts = `-- name: CountAccounts :one
+SELECT count(*) FROM accounts
+WHERE owner = $1
+`
+
+func (q *Queries) CountAccounts(ctx context.Context, owner string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAccounts, owner)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
     owner,
     balance,
-    currency
+    currency,
+    type,
+    nickname
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+    $1, $2, $3, $4, $5
+) RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Type     string `json:"type"`
+	Nickname string `json:"nickname"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Type,
+		arg.Nickname,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -57,6 +89,12 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.BlockCredits,
+		&i.Type,
+		&i.Nickname,
 	)
 	return i, err
 }
@@ -72,7 +110,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -85,12 +123,18 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.BlockCredits,
+		&i.Type,
+		&i.Nickname,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -104,26 +148,93 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.BlockCredits,
+		&i.Type,
+		&i.Nickname,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname FROM accounts
 WHERE owner = $1
+  AND ($2::bigint IS NULL OR id > $2::bigint)
 ORDER BY id
-LIMIT $2
-OFFSET $3
+LIMIT $3
 `
 
 type ListAccountsParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Owner   string        `json:"owner"`
+	AfterID sql.NullInt64 `json:"after_id"`
+	Limit   int32         `json:"limit"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.ClosedAt,
+			&i.Status,
+			&i.BlockCredits,
+			&i.Type,
+			&i.Nickname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsByTypes = `-- name: ListAccountsByTypes :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname FROM accounts
+WHERE type = ANY($1::varchar[])
+  AND status <> 'closed'
+  AND created_at < $2
+  AND id > $3
+  AND NOT EXISTS (
+    SELECT 1 FROM interest_expense_accounts
+    WHERE interest_expense_accounts.account_id = accounts.id
+)
+ORDER BY id
+LIMIT $4
+`
+
+type ListAccountsByTypesParams struct {
+	Types         []string  `json:"types"`
+	CreatedBefore time.Time `json:"created_before"`
+	AfterID       int64     `json:"after_id"`
+	Limit         int32     `json:"limit"`
+}
+
+func (q *Queries) ListAccountsByTypes(ctx context.Context, arg ListAccountsByTypesParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountsByTypes,
+		pq.Array(arg.Types),
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +248,12 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.ClosedAt,
+			&i.Status,
+			&i.BlockCredits,
+			&i.Type,
+			&i.Nickname,
 		); err != nil {
 			return nil, err
 		}
@@ -155,7 +272,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname
 `
 
 type UpdateAccountParams struct {
@@ -172,6 +289,77 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.BlockCredits,
+		&i.Type,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.BlockCredits,
+		&i.Type,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $1,
+    block_credits = $2,
+    closed_at = CASE WHEN $1::varchar = 'closed' THEN now() ELSE closed_at END
+WHERE id = $3
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, block_credits, type, nickname
+`
+
+type UpdateAccountStatusParams struct {
+	Status       string `json:"status"`
+	BlockCredits bool   `json:"block_credits"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.Status, arg.BlockCredits, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.BlockCredits,
+		&i.Type,
+		&i.Nickname,
 	)
 	return i, err
 }
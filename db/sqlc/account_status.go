@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+)
+
+var (
+	// ErrAccountFrozen is returned when a frozen account would be debited, or credited while credits are blocked
+	ErrAccountFrozen = errors.New("account is frozen")
+	// ErrAccountNotEmpty is returned when an account with a non-zero balance would be closed
+	ErrAccountNotEmpty = errors.New("account balance must be zero to close the account")
+	// ErrInvalidStatusChange is returned when an account status change is not allowed from the current status
+	ErrInvalidStatusChange = errors.New("invalid account status change")
+)
+
+// ChangeAccountStatusTxParams contains the input parameters of the account status change transaction
+type ChangeAccountStatusTxParams struct {
+	AccountID int64               `json:"account_id"`
+	Status    model.AccountStatus `json:"status"`
+	// BlockCredits only applies to frozen accounts, it is always cleared otherwise
+	BlockCredits bool   `json:"block_credits"`
+	Reason       string `json:"reason"`
+	ChangedBy    string `json:"changed_by"`
+}
+
+// ChangeAccountStatusTxResult is the result of the account status change transaction
+type ChangeAccountStatusTxResult struct {
+	Account      Account             `json:"account"`
+	StatusChange AccountStatusChange `json:"status_change"`
+}
+
+// ChangeAccountStatusTx moves an account to a new status and records the change in the audit trail.
+// The account row is locked first, so the change is serialized with transfers touching the account.
+// Closed accounts cannot change status, only active accounts with a zero balance and no pending holds can be closed,
+// and a change to the current status returns ErrInvalidStatusChange.
+func (store SQLStore) ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error) {
+	var result ChangeAccountStatusTxResult
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		blockCredits := arg.Status == model.AccountStatusFrozen && arg.BlockCredits
+		err = checkStatusChange(account, arg.Status, blockCredits)
+		if err != nil {
+			return err
+		}
+
+		if arg.Status == model.AccountStatusClosed {
+			held, err := q.SumActiveHolds(ctx, arg.AccountID)
+			if err != nil {
+				return err
+			}
+			if held != 0 {
+				return fmt.Errorf("%w: account %d has %d on hold", ErrAccountNotEmpty, arg.AccountID, held)
+			}
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			Status:       string(arg.Status),
+			BlockCredits: blockCredits,
+			ID:           arg.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.StatusChange, err = q.CreateAccountStatusChange(ctx, CreateAccountStatusChangeParams{
+			AccountID:    arg.AccountID,
+			FromStatus:   account.Status,
+			ToStatus:     string(arg.Status),
+			BlockCredits: blockCredits,
+			Reason:       arg.Reason,
+			ChangedBy:    arg.ChangedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// checkStatusChange checks if the account is allowed to move to the given status
+func checkStatusChange(account Account, status model.AccountStatus, blockCredits bool) error {
+	current := model.AccountStatus(account.Status)
+	switch {
+	case !status.IsValid():
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidStatusChange, status)
+	case current == model.AccountStatusClosed:
+		return fmt.Errorf("%w: account %d", ErrAccountClosed, account.ID)
+	case current == status && account.BlockCredits == blockCredits:
+		return fmt.Errorf("%w: account %d is already %s", ErrInvalidStatusChange, account.ID, status)
+	case status == model.AccountStatusClosed && current == model.AccountStatusFrozen:
+		return fmt.Errorf("%w: account %d", ErrAccountFrozen, account.ID)
+	case status == model.AccountStatusClosed && account.Balance != 0:
+		return fmt.Errorf("%w: account %d has a balance of %d", ErrAccountNotEmpty, account.ID, account.Balance)
+	}
+	return nil
+}
+
+// checkAccountDebit checks if money can be taken out of the account
+func checkAccountDebit(account Account) error {
+	switch model.AccountStatus(account.Status) {
+	case model.AccountStatusClosed:
+		return fmt.Errorf("%w: account %d", ErrAccountClosed, account.ID)
+	case model.AccountStatusFrozen:
+		return fmt.Errorf("%w: account %d", ErrAccountFrozen, account.ID)
+	}
+	return nil
+}
+
+// checkAccountCredit checks if money can be put into the account
+func checkAccountCredit(account Account) error {
+	switch model.AccountStatus(account.Status) {
+	case model.AccountStatusClosed:
+		return fmt.Errorf("%w: account %d", ErrAccountClosed, account.ID)
+	case model.AccountStatusFrozen:
+		if account.BlockCredits {
+			return fmt.Errorf("%w: account %d does not accept credits", ErrAccountFrozen, account.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	Context("Account status", func() {
+		changeStatus := func(account Account, status model.AccountStatus, blockCredits bool) (ChangeAccountStatusTxResult, error) {
+			store := NewStore(testDB)
+			return store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+				AccountID:    account.ID,
+				Status:       status,
+				BlockCredits: blockCredits,
+				Reason:       "changed by test",
+				ChangedBy:    account.Owner,
+			})
+		}
+
+		It("Test new accounts are active", func() {
+			account := createRandomAccount()
+			Expect(account.Status).To(Equal(string(model.AccountStatusActive)))
+			Expect(account.BlockCredits).To(BeFalse())
+		})
+
+		It("Test freeze and unfreeze with audit trail", func() {
+			account := createRandomAccount()
+
+			result, err := changeStatus(account, model.AccountStatusFrozen, true)
+			Expect(err).To(BeNil())
+			Expect(result.Account.Status).To(Equal(string(model.AccountStatusFrozen)))
+			Expect(result.Account.BlockCredits).To(BeTrue())
+			Expect(result.StatusChange.FromStatus).To(Equal(string(model.AccountStatusActive)))
+			Expect(result.StatusChange.ToStatus).To(Equal(string(model.AccountStatusFrozen)))
+			Expect(result.StatusChange.Reason).To(Equal("changed by test"))
+			Expect(result.StatusChange.ChangedBy).To(Equal(account.Owner))
+
+			// Freezing again with the same flag is not a change
+			_, err = changeStatus(account, model.AccountStatusFrozen, true)
+			Expect(errors.Is(err, ErrInvalidStatusChange)).To(BeTrue())
+
+			result, err = changeStatus(account, model.AccountStatusActive, true)
+			Expect(err).To(BeNil())
+			Expect(result.Account.Status).To(Equal(string(model.AccountStatusActive)))
+			Expect(result.Account.BlockCredits).To(BeFalse())
+
+			changes, err := testQueries.ListAccountStatusChanges(context.Background(), ListAccountStatusChangesParams{
+				AccountID: account.ID,
+				Limit:     5,
+				Offset:    0,
+			})
+			Expect(err).To(BeNil())
+			Expect(changes).To(HaveLen(2))
+			Expect(changes[0].ToStatus).To(Equal(string(model.AccountStatusFrozen)))
+			Expect(changes[1].ToStatus).To(Equal(string(model.AccountStatusActive)))
+		})
+
+		It("Test close account", func() {
+			account := createRandomAccountWithBalance(0)
+
+			result, err := changeStatus(account, model.AccountStatusClosed, false)
+			Expect(err).To(BeNil())
+			Expect(result.Account.Status).To(Equal(string(model.AccountStatusClosed)))
+			Expect(result.Account.ClosedAt.Valid).To(BeTrue())
+
+			// A closed account stays closed
+			_, err = changeStatus(account, model.AccountStatusActive, false)
+			Expect(errors.Is(err, ErrAccountClosed)).To(BeTrue())
+		})
+
+		It("Test close account with non zero balance", func() {
+			account := createRandomAccount()
+
+			_, err := changeStatus(account, model.AccountStatusClosed, false)
+			Expect(errors.Is(err, ErrAccountNotEmpty)).To(BeTrue())
+
+			account, err = testQueries.GetAccount(context.Background(), account.ID)
+			Expect(err).To(BeNil())
+			Expect(account.Status).To(Equal(string(model.AccountStatusActive)))
+			Expect(account.ClosedAt.Valid).To(BeFalse())
+		})
+
+		It("Test close frozen account", func() {
+			account := createRandomAccountWithBalance(0)
+
+			_, err := changeStatus(account, model.AccountStatusFrozen, false)
+			Expect(err).To(BeNil())
+
+			_, err = changeStatus(account, model.AccountStatusClosed, false)
+			Expect(errors.Is(err, ErrAccountFrozen)).To(BeTrue())
+		})
+
+		It("Test frozen account cannot be debited", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+
+			_, err := changeStatus(account1, model.AccountStatusFrozen, false)
+			Expect(err).To(BeNil())
+
+			_, err = store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        10,
+			})
+			Expect(errors.Is(err, ErrAccountFrozen)).To(BeTrue())
+
+			// Credits are still accepted unless they are blocked too
+			_, err = store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account2.ID,
+				ToAccountID:   account1.ID,
+				Amount:        10,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = changeStatus(account1, model.AccountStatusFrozen, true)
+			Expect(err).To(BeNil())
+
+			_, err = store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account2.ID,
+				ToAccountID:   account1.ID,
+				Amount:        10,
+			})
+			Expect(errors.Is(err, ErrAccountFrozen)).To(BeTrue())
+		})
+	})
+})
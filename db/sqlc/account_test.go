@@ -2,9 +2,11 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -26,6 +28,7 @@ func createRandomUser() User {
 	user, err := testQueries.CreateUsers(context.Background(), arg)
 	Expect(err).To(BeNil())
 	Expect(user.Username).To(Equal(arg.Username))
+	Expect(user.Role).To(Equal("customer"))
 
 	return user
 }
@@ -40,6 +43,7 @@ var _ = Describe("Operation", func() {
 			Owner:    testOwnerName.Username,
 			Balance:  testBalance,
 			Currency: testCurrency,
+			Type:     string(model.AccountTypeChecking),
 		}
 
 		account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -60,6 +64,7 @@ var _ = Describe("Operation", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -89,6 +94,7 @@ var _ = Describe("Operation", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -117,15 +123,15 @@ var _ = Describe("Operation", func() {
 			testBalance := util.GetRandomMoneyAmount()
 			testCurrency := util.GetRandomCurrency()
 			arg1 := ListAccountsParams{
-				Owner:  testOwnerName.Username,
-				Limit:  5,
-				Offset: 0,
+				Owner: testOwnerName.Username,
+				Limit: 5,
 			}
 
 			arg2 := CreateAccountParams{
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			testAccount, err := testQueries.CreateAccount(context.Background(), arg2)
@@ -138,6 +144,73 @@ var _ = Describe("Operation", func() {
 			Expect(len(accounts)).To(BeNumerically(">=", 1))
 		})
 
+		It("Test several accounts in the same currency", func() {
+			owner := createRandomUser()
+			create := func(accountType model.AccountType, nickname string) (Account, error) {
+				return testQueries.CreateAccount(context.Background(), CreateAccountParams{
+					Owner:    owner.Username,
+					Currency: string(model.USD),
+					Type:     string(accountType),
+					Nickname: nickname,
+				})
+			}
+
+			checking, err := create(model.AccountTypeChecking, "")
+			Expect(err).To(BeNil())
+			Expect(checking.Type).To(Equal(string(model.AccountTypeChecking)))
+
+			savings, err := create(model.AccountTypeSavings, "Rainy day")
+			Expect(err).To(BeNil())
+			Expect(savings.Type).To(Equal(string(model.AccountTypeSavings)))
+			Expect(savings.Nickname).To(Equal("Rainy day"))
+
+			_, err = create(model.AccountTypeChecking, "")
+			Expect(err).To(BeNil())
+
+			// Nicknames are unique among the accounts of the owner
+			_, err = create(model.AccountTypeChecking, "Rainy day")
+			Expect(err).NotTo(BeNil())
+
+			_, err = create("brokerage", "")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("Test ListAccounts pages after a cursor", func() {
+			owner := createRandomUser()
+			ids := []int64{}
+			for _, currency := range []string{"USD", "EUR", "CAD"} {
+				account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+					Owner:    owner.Username,
+					Currency: currency,
+					Type:     string(model.AccountTypeChecking),
+				})
+				Expect(err).To(BeNil())
+				ids = append(ids, account.ID)
+			}
+
+			count, err := testQueries.CountAccounts(context.Background(), owner.Username)
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(3)))
+
+			page, err := testQueries.ListAccounts(context.Background(), ListAccountsParams{
+				Owner: owner.Username,
+				Limit: 2,
+			})
+			Expect(err).To(BeNil())
+			Expect(page).To(HaveLen(2))
+			Expect(page[0].ID).To(Equal(ids[0]))
+			Expect(page[1].ID).To(Equal(ids[1]))
+
+			page, err = testQueries.ListAccounts(context.Background(), ListAccountsParams{
+				Owner:   owner.Username,
+				AfterID: sql.NullInt64{Int64: page[1].ID, Valid: true},
+				Limit:   2,
+			})
+			Expect(err).To(BeNil())
+			Expect(page).To(HaveLen(1))
+			Expect(page[0].ID).To(Equal(ids[2]))
+		})
+
 		It("Test DeleteAccountParams", func() {
 			testOwnerName := createRandomUser()
 			testBalance := util.GetRandomMoneyAmount()
@@ -146,6 +219,7 @@ var _ = Describe("Operation", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, err := testQueries.CreateAccount(context.Background(), testAccount)
@@ -170,6 +244,7 @@ var _ = Describe("Operation", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -199,6 +274,7 @@ var _ = Describe("Operation", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, err := testQueries.CreateAccount(context.Background(), testAccount)
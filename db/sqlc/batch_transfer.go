@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// BatchTransferLeg is a single transfer of a batch
+type BatchTransferLeg struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+	// ToAmount is the amount credited to the destination account, it differs from Amount for cross-currency legs
+	ToAmount int64 `json:"to_amount"`
+	// ExchangeRate is the rate that was applied to Amount to get ToAmount
+	ExchangeRate string `json:"exchange_rate"`
+}
+
+// BatchTransferTxParams contains the input parameters of the batch transfer transaction
+type BatchTransferTxParams struct {
+	Legs []BatchTransferLeg `json:"legs"`
+}
+
+// BatchTransferTxResult is the result of the batch transfer transaction, with the result of each leg in order
+type BatchTransferTxResult struct {
+	Legs []TransferTxResult `json:"legs"`
+}
+
+// BatchLegError is returned when a leg of a batch transfer fails, it wraps the error of the leg
+type BatchLegError struct {
+	// Leg is the index of the failed leg in the batch
+	Leg int
+	Err error
+}
+
+func (e *BatchLegError) Error() string {
+	return fmt.Sprintf("leg %d: %v", e.Leg, e.Err)
+}
+
+func (e *BatchLegError) Unwrap() error {
+	return e.Err
+}
+
+// BatchTransferTx performs all the legs of a batch in a single database transaction, so either every leg
+// is transferred or none is. Every account of the batch is locked in ID order before the first leg,
+// so batches and transfers sharing accounts cannot deadlock.
+// The legs are applied in order and each one is checked like a TransferTx,
+// a failing leg rolls back the whole batch and is returned as a *BatchLegError.
+func (store SQLStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		result.Legs = make([]TransferTxResult, len(arg.Legs))
+
+		for _, accountID := range batchAccountIDs(arg.Legs) {
+			_, err := q.GetAccountForUpdate(ctx, accountID)
+			if err != nil {
+				return fmt.Errorf("lock account %d: %w", accountID, err)
+			}
+		}
+
+		for i, leg := range arg.Legs {
+			legResult, err := transfer(ctx, q, CreateTransferParams{
+				FromAccountID: leg.FromAccountID,
+				ToAccountID:   leg.ToAccountID,
+				Amount:        leg.Amount,
+				ToAmount:      leg.ToAmount,
+				ExchangeRate:  leg.ExchangeRate,
+			})
+			if err == nil {
+				err = checkAvailableBalance(ctx, q, legResult.FromAccount)
+			}
+			if err != nil {
+				return &BatchLegError{Leg: i, Err: err}
+			}
+			result.Legs[i] = legResult
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// batchAccountIDs returns the distinct accounts of the legs in ascending order
+func batchAccountIDs(legs []BatchTransferLeg) []int64 {
+	seen := make(map[int64]bool)
+	ids := []int64{}
+	for _, leg := range legs {
+		for _, id := range []int64{leg.FromAccountID, leg.ToAccountID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
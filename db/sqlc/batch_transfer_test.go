@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	Context("Batch transfers", func() {
+		payroll := func(from Account, employees []Account, amount int64) BatchTransferTxParams {
+			arg := BatchTransferTxParams{}
+			for _, employee := range employees {
+				arg.Legs = append(arg.Legs, BatchTransferLeg{
+					FromAccountID: from.ID,
+					ToAccountID:   employee.ID,
+					Amount:        amount,
+					ToAmount:      amount,
+					ExchangeRate:  "1",
+				})
+			}
+			return arg
+		}
+
+		It("Test payroll pays every leg", func() {
+			store := NewStore(testDB)
+			employer := createRandomAccountWithBalance(1000)
+			employees := []Account{createRandomAccountWithBalance(0), createRandomAccountWithBalance(0), createRandomAccountWithBalance(0)}
+
+			result, err := store.BatchTransferTx(context.Background(), payroll(employer, employees, 300))
+			Expect(err).To(BeNil())
+			Expect(result.Legs).To(HaveLen(3))
+			for i, leg := range result.Legs {
+				Expect(leg.Transfer.ToAccountID).To(Equal(employees[i].ID))
+				Expect(leg.ToAccount.Balance).To(Equal(int64(300)))
+				Expect(leg.FromEntry.Amount).To(Equal(int64(-300)))
+			}
+			Expect(result.Legs[2].FromAccount.Balance).To(Equal(int64(100)))
+		})
+
+		It("Test failing leg rolls back the batch", func() {
+			store := NewStore(testDB)
+			employer := createRandomAccountWithBalance(500)
+			employees := []Account{createRandomAccountWithBalance(0), createRandomAccountWithBalance(0), createRandomAccountWithBalance(0)}
+
+			_, err := store.BatchTransferTx(context.Background(), payroll(employer, employees, 200))
+			Expect(errors.Is(err, ErrInsufficientFunds)).To(BeTrue())
+			var legErr *BatchLegError
+			Expect(errors.As(err, &legErr)).To(BeTrue())
+			Expect(legErr.Leg).To(Equal(2))
+
+			for _, account := range append(employees, employer) {
+				updated, err := testQueries.GetAccount(context.Background(), account.ID)
+				Expect(err).To(BeNil())
+				Expect(updated.Balance).To(Equal(account.Balance))
+			}
+			transfers, err := testQueries.ListTransfers(context.Background(), ListTransfersParams{
+				FromAccountID: employer.ID,
+				ToAccountID:   employer.ID,
+				Limit:         5,
+				Offset:        0,
+			})
+			Expect(err).To(BeNil())
+			Expect(transfers).To(BeEmpty())
+		})
+
+		It("Test concurrent opposing batches do not deadlock", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(1000)
+			account2 := createRandomAccountWithBalance(1000)
+			account3 := createRandomAccountWithBalance(1000)
+
+			n := 10
+			errs := make(chan error)
+			for i := 0; i < n; i++ {
+				// Every other batch walks the accounts in the opposite order
+				legs := []BatchTransferLeg{
+					{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10, ToAmount: 10, ExchangeRate: "1"},
+					{FromAccountID: account2.ID, ToAccountID: account3.ID, Amount: 10, ToAmount: 10, ExchangeRate: "1"},
+				}
+				if i%2 == 1 {
+					legs = []BatchTransferLeg{
+						{FromAccountID: account3.ID, ToAccountID: account2.ID, Amount: 10, ToAmount: 10, ExchangeRate: "1"},
+						{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 10, ToAmount: 10, ExchangeRate: "1"},
+					}
+				}
+				go func() {
+					_, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{Legs: legs})
+					errs <- err
+				}()
+			}
+
+			for i := 0; i < n; i++ {
+				Expect(<-errs).To(BeNil())
+			}
+
+			for _, account := range []Account{account1, account2, account3} {
+				updated, err := testQueries.GetAccount(context.Background(), account.ID)
+				Expect(err).To(BeNil())
+				Expect(updated.Balance).To(Equal(account.Balance))
+			}
+		})
+	})
+})
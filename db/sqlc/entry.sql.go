@@ -5,36 +5,53 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transfer_id,
+    description,
+    external_reference
 ) VALUES (
-    $1, $2
-) RETURNING id, account_id, amount, created_at
+    $1, $2, $3, $4, $5
+) RETURNING id, account_id, amount, created_at, transfer_id, description, external_reference
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID         int64         `json:"account_id"`
+	Amount            int64         `json:"amount"`
+	TransferID        sql.NullInt64 `json:"transfer_id"`
+	Description       string        `json:"description"`
+	ExternalReference string        `json:"external_reference"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.Description,
+		arg.ExternalReference,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Description,
+		&i.ExternalReference,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, description, external_reference FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -46,12 +63,15 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Description,
+		&i.ExternalReference,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, description, external_reference FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -78,6 +98,9 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.Description,
+			&i.ExternalReference,
 		); err != nil {
 			return nil, err
 		}
@@ -91,3 +114,84 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	}
 	return items, nil
 }
+
+const listEntriesBetween = `-- name: ListEntriesBetween :many
+SELECT id, account_id, amount, created_at, transfer_id, description, external_reference FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+ORDER BY created_at, id
+`
+
+type ListEntriesBetweenParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+func (q *Queries) ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesBetween, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.Description,
+			&i.ExternalReference,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumEntriesBefore = `-- name: SumEntriesBefore :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = $1
+  AND created_at < $2
+`
+
+type SumEntriesBeforeParams struct {
+	AccountID int64     `json:"account_id"`
+	Before    time.Time `json:"before"`
+}
+
+func (q *Queries) SumEntriesBefore(ctx context.Context, arg SumEntriesBeforeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumEntriesBefore, arg.AccountID, arg.Before)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const sumEntriesSince = `-- name: SumEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+`
+
+type SumEntriesSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	Since     time.Time `json:"since"`
+}
+
+func (q *Queries) SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumEntriesSince, arg.AccountID, arg.Since)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -19,6 +20,7 @@ var _ = Describe("SQL Entry Operations", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, _ := testQueries.CreateAccount(context.Background(), arg)
@@ -41,6 +43,7 @@ var _ = Describe("SQL Entry Operations", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, _ := testQueries.CreateAccount(context.Background(), arg)
@@ -67,6 +70,7 @@ var _ = Describe("SQL Entry Operations", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, _ := testQueries.CreateAccount(context.Background(), arg)
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// errInjectedFault is the message of the error returned by a query that faultDBTX makes fail
+const errInjectedFault = "injected fault"
+
+// faultDBTX wraps a connection and fails its Nth query, counting from 1.
+// With failAt set to 0 no query fails, which is useful to count the queries of an operation.
+type faultDBTX struct {
+	DBTX
+	failAt  int
+	code    pq.ErrorCode
+	queries int
+}
+
+// faultInjector creates stores whose transactions fail at their Nth query.
+// Every transaction gets a counter of its own, so failAt applies to each one independently.
+type faultInjector struct {
+	failAt int
+	// code is the Postgres error code of the failure, raise_exception if it is not set
+	code pq.ErrorCode
+	// faultyTxs limits the failures to the first transactions, every transaction fails if it is 0
+	faultyTxs int
+	txs       []*faultDBTX
+}
+
+// store creates a store that wraps the connection of each transaction with a faultDBTX
+func (injector *faultInjector) store(db *sql.DB) SQLStore {
+	return SQLStore{
+		Queries: New(db),
+		db:      db,
+		wrapTx: func(conn DBTX) DBTX {
+			tx := &faultDBTX{DBTX: conn, failAt: injector.failAt, code: injector.code}
+			if injector.faultyTxs > 0 && len(injector.txs) >= injector.faultyTxs {
+				tx.failAt = 0
+			}
+			if tx.code == "" {
+				tx.code = "P0001"
+			}
+			injector.txs = append(injector.txs, tx)
+			return tx
+		},
+	}
+}
+
+// fail counts the query and reports whether it is the one to fail
+func (f *faultDBTX) fail() bool {
+	f.queries++
+	return f.queries == f.failAt
+}
+
+// err returns the error of the failed query, as the driver would report it
+func (f *faultDBTX) err() error {
+	return &pq.Error{Code: f.code, Message: errInjectedFault}
+}
+
+func (f *faultDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if f.fail() {
+		return nil, f.err()
+	}
+	return f.DBTX.ExecContext(ctx, query, args...)
+}
+
+func (f *faultDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if f.fail() {
+		return nil, f.err()
+	}
+	return f.DBTX.PrepareContext(ctx, query)
+}
+
+func (f *faultDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if f.fail() {
+		return nil, f.err()
+	}
+	return f.DBTX.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext cannot return an error of its own, so the failure is raised by the server instead.
+// This also aborts the transaction on the server like a real failure would.
+func (f *faultDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if f.fail() {
+		faultQuery := fmt.Sprintf(`DO $$ BEGIN RAISE EXCEPTION '%s' USING ERRCODE = '%s'; END $$`, errInjectedFault, f.code)
+		return f.DBTX.QueryRowContext(ctx, faultQuery)
+	}
+	return f.DBTX.QueryRowContext(ctx, query, args...)
+}
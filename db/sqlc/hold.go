@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// HoldStatusPending is the status of a hold that still reserves funds
+	HoldStatusPending = "pending"
+	// HoldStatusCaptured is the status of a hold that was turned into a transfer
+	HoldStatusCaptured = "captured"
+	// HoldStatusReleased is the status of a hold that was released or expired
+	HoldStatusReleased = "released"
+)
+
+var (
+	// ErrHoldNotPending is returned when a hold that was already captured or released would be captured or released
+	ErrHoldNotPending = errors.New("hold is not pending")
+	// ErrHoldExpired is returned when an expired hold would be captured
+	ErrHoldExpired = errors.New("hold is expired")
+	// ErrInvalidCaptureAmount is returned when a capture amount is not positive or exceeds the held amount
+	ErrInvalidCaptureAmount = errors.New("invalid capture amount")
+)
+
+// PlaceHoldParams contains the input parameters of the place hold transaction
+type PlaceHoldParams struct {
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// PlaceHold reserves an amount of the account for a later capture to the destination account.
+// The balance is not changed, but the amount no longer counts towards the available balance.
+// It returns ErrInsufficientFunds if the available balance would go below the overdraft limit.
+func (store SQLStore) PlaceHold(ctx context.Context, arg PlaceHoldParams) (Hold, error) {
+	var hold Hold
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		// The account row lock serializes holds with transfers out of the account
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountDebit(account); err != nil {
+			return err
+		}
+
+		held, err := q.SumActiveHolds(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Balance-held-arg.Amount < -account.OverdraftLimit {
+			return fmt.Errorf("%w: account %d", ErrInsufficientFunds, arg.AccountID)
+		}
+
+		hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID:   arg.AccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount:      arg.Amount,
+			ExpiresAt:   arg.ExpiresAt,
+		})
+		return err
+	})
+
+	return hold, err
+}
+
+// CaptureHoldParams contains the input parameters of the capture hold transaction
+type CaptureHoldParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount is optional. Zero captures the full held amount, the rest of a partial capture is released.
+	Amount int64 `json:"amount"`
+}
+
+// CaptureHoldResult is the result of the capture hold transaction
+type CaptureHoldResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CaptureHold moves the held amount to the destination account of the hold and closes the hold.
+// It returns ErrHoldNotPending if the hold was already captured or released, and ErrHoldExpired if it expired.
+func (store SQLStore) CaptureHold(ctx context.Context, arg CaptureHoldParams) (CaptureHoldResult, error) {
+	var result CaptureHoldResult
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+		if err := checkHoldPending(hold); err != nil {
+			return err
+		}
+		if !hold.ExpiresAt.After(time.Now()) {
+			return fmt.Errorf("%w: hold %d", ErrHoldExpired, hold.ID)
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount < 0 || amount > hold.Amount {
+			return fmt.Errorf("%w: %d of %d held", ErrInvalidCaptureAmount, amount, hold.Amount)
+		}
+
+		// The funds were reserved when the hold was placed, so only the overdraft limit is checked here
+		result.Transfer, err = transfer(ctx, q, CreateTransferParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        amount,
+			ToAmount:      amount,
+			ExchangeRate:  "1",
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.MarkHoldCaptured(ctx, MarkHoldCapturedParams{
+			ID:         hold.ID,
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ReleaseHold releases a pending hold, so the amount counts towards the available balance again.
+// It returns ErrHoldNotPending if the hold was already captured or released.
+func (store SQLStore) ReleaseHold(ctx context.Context, holdID int64) (Hold, error) {
+	var hold Hold
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		current, err := q.GetHoldForUpdate(ctx, holdID)
+		if err != nil {
+			return err
+		}
+		if err := checkHoldPending(current); err != nil {
+			return err
+		}
+
+		hold, err = q.MarkHoldReleased(ctx, holdID)
+		return err
+	})
+
+	return hold, err
+}
+
+// checkHoldPending checks if the hold still reserves funds
+func checkHoldPending(hold Hold) error {
+	if hold.Status != HoldStatusPending {
+		return fmt.Errorf("%w: hold %d is %s", ErrHoldNotPending, hold.ID, hold.Status)
+	}
+	return nil
+}
+
+// checkAvailableBalance checks that the pending holds of the account are still covered after a debit.
+// It must be called with the account row locked.
+func checkAvailableBalance(ctx context.Context, q *Queries, account Account) error {
+	held, err := q.SumActiveHolds(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	if account.Balance-held < -account.OverdraftLimit {
+		return fmt.Errorf("%w: account %d has %d on hold", ErrInsufficientFunds, account.ID, held)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	Context("Holds", func() {
+		placeHold := func(from, to Account, amount int64, expiresAt time.Time) (Hold, error) {
+			store := NewStore(testDB)
+			return store.PlaceHold(context.Background(), PlaceHoldParams{
+				AccountID:   from.ID,
+				ToAccountID: to.ID,
+				Amount:      amount,
+				ExpiresAt:   expiresAt,
+			})
+		}
+
+		It("Test place hold reduces available balance", func() {
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccount()
+
+			hold, err := placeHold(account1, account2, 60, time.Now().Add(time.Hour))
+			Expect(err).To(BeNil())
+			Expect(hold.Status).To(Equal(HoldStatusPending))
+			Expect(hold.Amount).To(Equal(int64(60)))
+			Expect(hold.TransferID.Valid).To(BeFalse())
+
+			held, err := testQueries.SumActiveHolds(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(held).To(Equal(int64(60)))
+
+			// The balance is untouched until the hold is captured
+			account, err := testQueries.GetAccount(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(account.Balance).To(Equal(int64(100)))
+
+			_, err = placeHold(account1, account2, 50, time.Now().Add(time.Hour))
+			Expect(errors.Is(err, ErrInsufficientFunds)).To(BeTrue())
+
+			// A transfer cannot spend the funds on hold either
+			store := NewStore(testDB)
+			_, err = store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        50,
+			})
+			Expect(errors.Is(err, ErrInsufficientFunds)).To(BeTrue())
+		})
+
+		It("Test capture hold", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+
+			hold, err := placeHold(account1, account2, 60, time.Now().Add(time.Hour))
+			Expect(err).To(BeNil())
+
+			result, err := store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: hold.ID, Amount: 40})
+			Expect(err).To(BeNil())
+			Expect(result.Hold.Status).To(Equal(HoldStatusCaptured))
+			Expect(result.Hold.TransferID.Int64).To(Equal(result.Transfer.Transfer.ID))
+			Expect(result.Transfer.Transfer.Amount).To(Equal(int64(40)))
+			Expect(result.Transfer.FromAccount.Balance).To(Equal(int64(60)))
+			Expect(result.Transfer.ToAccount.Balance).To(Equal(int64(40)))
+
+			// The rest of a partial capture is no longer held
+			held, err := testQueries.SumActiveHolds(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(held).To(BeZero())
+
+			_, err = store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: hold.ID})
+			Expect(errors.Is(err, ErrHoldNotPending)).To(BeTrue())
+		})
+
+		It("Test capture more than held", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccount()
+
+			hold, err := placeHold(account1, account2, 60, time.Now().Add(time.Hour))
+			Expect(err).To(BeNil())
+
+			_, err = store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: hold.ID, Amount: 61})
+			Expect(errors.Is(err, ErrInvalidCaptureAmount)).To(BeTrue())
+		})
+
+		It("Test release hold", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccount()
+
+			hold, err := placeHold(account1, account2, 60, time.Now().Add(time.Hour))
+			Expect(err).To(BeNil())
+
+			released, err := store.ReleaseHold(context.Background(), hold.ID)
+			Expect(err).To(BeNil())
+			Expect(released.Status).To(Equal(HoldStatusReleased))
+
+			held, err := testQueries.SumActiveHolds(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(held).To(BeZero())
+
+			_, err = store.ReleaseHold(context.Background(), hold.ID)
+			Expect(errors.Is(err, ErrHoldNotPending)).To(BeTrue())
+			_, err = store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: hold.ID})
+			Expect(errors.Is(err, ErrHoldNotPending)).To(BeTrue())
+		})
+
+		It("Test expired holds are released", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccount()
+
+			hold, err := placeHold(account1, account2, 60, time.Now().Add(-time.Minute))
+			Expect(err).To(BeNil())
+
+			// An expired hold stops reserving funds before the release runs
+			held, err := testQueries.SumActiveHolds(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(held).To(BeZero())
+
+			_, err = store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: hold.ID})
+			Expect(errors.Is(err, ErrHoldExpired)).To(BeTrue())
+
+			released, err := testQueries.ReleaseExpiredHolds(context.Background())
+			Expect(err).To(BeNil())
+			Expect(released).To(BeNumerically(">=", 1))
+
+			hold, err = testQueries.GetHold(context.Background(), hold.ID)
+			Expect(err).To(BeNil())
+			Expect(hold.Status).To(Equal(HoldStatusReleased))
+		})
+
+		It("Test account with pending holds cannot be closed", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(0)
+			account1, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+				ID:             account1.ID,
+				OverdraftLimit: 100,
+			})
+			Expect(err).To(BeNil())
+			account2 := createRandomAccount()
+
+			_, err = placeHold(account1, account2, 10, time.Now().Add(time.Hour))
+			Expect(err).To(BeNil())
+
+			_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+				AccountID: account1.ID,
+				Status:    model.AccountStatusClosed,
+				Reason:    "changed by test",
+				ChangedBy: account1.Owner,
+			})
+			Expect(errors.Is(err, ErrAccountNotEmpty)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/interest"
+	"github.com/Petatron/bank-simulator-backend/model"
+)
+
+// ErrInterestAlreadyPosted is returned when the interest of an account is posted twice for the same month
+var ErrInterestAlreadyPosted = errors.New("interest is already posted")
+
+// InterestExpenseOwner owns the interest expense accounts of the bank. Usernames are alphanumeric,
+// so no user can register it.
+const InterestExpenseOwner = "bank.interest"
+
+// PostInterestTxParams contains the input parameters of the interest posting transaction
+type PostInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Period is any time in the month whose accruals are posted, it is truncated to the first day of the month in UTC
+	Period time.Time `json:"period"`
+}
+
+// PostInterestTxResult is the result of the interest posting transaction
+type PostInterestTxResult struct {
+	Posting InterestPosting `json:"posting"`
+	Account Account         `json:"account"`
+	// Entry and Transfer are only set when at least a whole minor unit was posted.
+	// Entry is the entry of the account, Transfer moves the interest from or to the interest expense account.
+	Entry    *Entry            `json:"entry,omitempty"`
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// PostInterestTx posts the interest an account accrued in a month and records the posting.
+// The accruals of the month and the carry of the previous posting are split by interest.SplitAccrued:
+// the whole minor units are paid by a transfer from the interest expense account of the currency, or charged
+// by a transfer to it when negative, and the remainder is carried to the next posting.
+// Interest is posted whatever the overdraft limit of the account. It is held in the carry instead while the account
+// cannot take it: a closed account, a frozen account that does not accept credits for interest paid,
+// and a frozen account for interest charged. The next posting after the block is lifted moves the held interest.
+// It returns ErrInterestAlreadyPosted if the month was already posted for the account.
+func (store SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+	period := time.Date(arg.Period.Year(), arg.Period.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		// The account row lock serializes postings of the same account
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.GetInterestPosting(ctx, GetInterestPostingParams{
+			AccountID: arg.AccountID,
+			Period:    period,
+		})
+		if err == nil {
+			return fmt.Errorf("%w: account %d for %s", ErrInterestAlreadyPosted, arg.AccountID, period.Format("2006-01"))
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		var carry int64
+		previous, err := q.GetLatestInterestPosting(ctx, GetLatestInterestPostingParams{
+			AccountID: arg.AccountID,
+			Before:    period,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil {
+			carry = previous.Carry
+		}
+
+		accrued, err := q.SumInterestAccruals(ctx, SumInterestAccrualsParams{
+			AccountID:   arg.AccountID,
+			PeriodStart: period,
+			PeriodEnd:   period.AddDate(0, 1, 0),
+		})
+		if err != nil {
+			return err
+		}
+		accrued += carry
+
+		amount, carry := interest.SplitAccrued(accrued)
+		if amount != 0 && !acceptsInterest(account, amount) {
+			amount, carry = 0, accrued
+		}
+
+		result.Account = account
+		var entryID, transferID sql.NullInt64
+		if amount != 0 {
+			expense, err := interestExpenseAccount(ctx, q, account.Currency)
+			if err != nil {
+				return err
+			}
+
+			transferArg := CreateTransferParams{
+				FromAccountID: expense.ID,
+				ToAccountID:   account.ID,
+				Amount:        amount,
+				ToAmount:      amount,
+				ExchangeRate:  "1",
+				Description:   fmt.Sprintf("Interest for %s", period.Format("January 2006")),
+			}
+			if amount < 0 {
+				transferArg.FromAccountID, transferArg.ToAccountID = account.ID, expense.ID
+				transferArg.Amount, transferArg.ToAmount = -amount, -amount
+			}
+			// The expense account goes below zero by the interest the bank paid, so no overdraft limit applies
+			transferResult, err := moveFunds(ctx, q, transferArg)
+			if err != nil {
+				return err
+			}
+			result.Transfer = &transferResult
+			transferID = sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true}
+
+			entry, updated := transferResult.ToEntry, transferResult.ToAccount
+			if amount < 0 {
+				entry, updated = transferResult.FromEntry, transferResult.FromAccount
+			}
+			result.Entry = &entry
+			result.Account = updated
+			entryID = sql.NullInt64{Int64: entry.ID, Valid: true}
+		}
+
+		result.Posting, err = q.CreateInterestPosting(ctx, CreateInterestPostingParams{
+			AccountID:  arg.AccountID,
+			Period:     period,
+			Accrued:    accrued,
+			Amount:     amount,
+			Carry:      carry,
+			EntryID:    entryID,
+			TransferID: transferID,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// acceptsInterest checks if the interest can be paid into the account, or charged from it when negative
+func acceptsInterest(account Account, amount int64) bool {
+	if amount < 0 {
+		return checkAccountDebit(account) == nil
+	}
+	return checkAccountCredit(account) == nil
+}
+
+// interestExpenseAccount returns the interest expense account of the currency, it is opened on its first use.
+// A transaction-scoped lock on the currency keeps concurrent postings from opening several accounts.
+func interestExpenseAccount(ctx context.Context, q *Queries, currency string) (Account, error) {
+	if err := q.LockInterestExpenseAccount(ctx, currency); err != nil {
+		return Account{}, err
+	}
+
+	account, err := q.GetInterestExpenseAccount(ctx, currency)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return account, err
+	}
+
+	account, err = q.CreateAccount(ctx, CreateAccountParams{
+		Owner:    InterestExpenseOwner,
+		Currency: currency,
+		Type:     string(model.AccountTypeChecking),
+		Nickname: fmt.Sprintf("Interest expense %s", currency),
+	})
+	if err != nil {
+		return account, err
+	}
+	_, err = q.CreateInterestExpenseAccount(ctx, CreateInterestExpenseAccountParams{
+		Currency:  currency,
+		AccountID: account.ID,
+	})
+	return account, err
+}
@@ -0,0 +1,270 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	Context("Interest", func() {
+		september := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+		october := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+		accrue := func(account Account, day time.Time, amount int64) (InterestAccrual, error) {
+			return testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+				AccountID:   account.ID,
+				AccrualDate: day,
+				Balance:     account.Balance,
+				AnnualRate:  "0.02",
+				Amount:      amount,
+			})
+		}
+
+		It("Test post interest carries the fraction of a minor unit", func() {
+			store := NewStore(testDB)
+			account := createRandomAccountWithBalance(0)
+
+			_, err := accrue(account, september, 1_500_000)
+			Expect(err).To(BeNil())
+			_, err = accrue(account, september.AddDate(0, 0, 1), 1_700_000)
+			Expect(err).To(BeNil())
+
+			// A day is only accrued once
+			_, err = accrue(account, september, 1_500_000)
+			Expect(errors.Is(err, sql.ErrNoRows)).To(BeTrue())
+
+			result, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september.AddDate(0, 0, 12),
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Posting.Period).To(BeTemporally("==", september))
+			Expect(result.Posting.Accrued).To(Equal(int64(3_200_000)))
+			Expect(result.Posting.Amount).To(Equal(int64(3)))
+			Expect(result.Posting.Carry).To(Equal(int64(200_000)))
+			Expect(result.Entry).NotTo(BeNil())
+			Expect(result.Entry.Amount).To(Equal(int64(3)))
+			Expect(result.Posting.EntryID.Int64).To(Equal(result.Entry.ID))
+			Expect(result.Account.Balance).To(Equal(int64(3)))
+
+			// The interest is paid by a transfer from the interest expense account of the currency
+			Expect(result.Transfer).NotTo(BeNil())
+			Expect(result.Entry.TransferID.Int64).To(Equal(result.Transfer.Transfer.ID))
+			Expect(result.Posting.TransferID.Int64).To(Equal(result.Transfer.Transfer.ID))
+			Expect(result.Transfer.FromAccount.Owner).To(Equal(InterestExpenseOwner))
+			Expect(result.Transfer.FromAccount.Currency).To(Equal(account.Currency))
+			Expect(result.Transfer.FromEntry.Amount).To(Equal(int64(-3)))
+
+			// A posted month is closed to accruals and cannot be posted again
+			_, err = accrue(account, september.AddDate(0, 0, 2), 1_000_000)
+			Expect(errors.Is(err, sql.ErrNoRows)).To(BeTrue())
+			_, err = store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september,
+			})
+			Expect(errors.Is(err, ErrInterestAlreadyPosted)).To(BeTrue())
+
+			// The carry is added to the next month
+			_, err = accrue(account, october, 900_000)
+			Expect(err).To(BeNil())
+			result, err = store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    october,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Posting.Accrued).To(Equal(int64(1_100_000)))
+			Expect(result.Posting.Amount).To(Equal(int64(1)))
+			Expect(result.Posting.Carry).To(Equal(int64(100_000)))
+			Expect(result.Account.Balance).To(Equal(int64(4)))
+		})
+
+		It("Test post interest below a minor unit", func() {
+			store := NewStore(testDB)
+			account := createRandomAccountWithBalance(0)
+
+			_, err := accrue(account, september, 400_000)
+			Expect(err).To(BeNil())
+
+			result, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Posting.Amount).To(BeZero())
+			Expect(result.Posting.Carry).To(Equal(int64(400_000)))
+			Expect(result.Posting.EntryID.Valid).To(BeFalse())
+			Expect(result.Entry).To(BeNil())
+			Expect(result.Account.Balance).To(BeZero())
+		})
+
+		It("Test post interest of a closed account", func() {
+			store := NewStore(testDB)
+			account := createRandomAccountWithBalance(0)
+
+			_, err := accrue(account, september, 2_000_000)
+			Expect(err).To(BeNil())
+			_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+				AccountID: account.ID,
+				Status:    model.AccountStatusClosed,
+				Reason:    "changed by test",
+				ChangedBy: account.Owner,
+			})
+			Expect(err).To(BeNil())
+
+			result, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Posting.Amount).To(BeZero())
+			Expect(result.Posting.Carry).To(Equal(int64(2_000_000)))
+			Expect(result.Entry).To(BeNil())
+		})
+
+		It("Test post interest charge", func() {
+			store := NewStore(testDB)
+			account := createRandomAccountWithBalance(0)
+
+			_, err := accrue(account, september, -1_500_000)
+			Expect(err).To(BeNil())
+
+			// Interest is charged whatever the overdraft limit
+			result, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Posting.Amount).To(Equal(int64(-1)))
+			Expect(result.Posting.Carry).To(Equal(int64(-500_000)))
+			Expect(result.Entry.Amount).To(Equal(int64(-1)))
+			Expect(result.Account.Balance).To(Equal(int64(-1)))
+			Expect(result.Transfer.ToAccount.Owner).To(Equal(InterestExpenseOwner))
+			Expect(result.Transfer.Transfer.Amount).To(Equal(int64(1)))
+		})
+
+		It("Test post interest holds it while the account does not accept credits", func() {
+			store := NewStore(testDB)
+			account := createRandomAccountWithBalance(0)
+
+			_, err := accrue(account, september, 2_000_000)
+			Expect(err).To(BeNil())
+			_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+				AccountID:    account.ID,
+				Status:       model.AccountStatusFrozen,
+				BlockCredits: true,
+				Reason:       "changed by test",
+				ChangedBy:    account.Owner,
+			})
+			Expect(err).To(BeNil())
+
+			result, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Posting.Amount).To(BeZero())
+			Expect(result.Posting.Carry).To(Equal(int64(2_000_000)))
+			Expect(result.Entry).To(BeNil())
+			Expect(result.Transfer).To(BeNil())
+			Expect(result.Account.Balance).To(BeZero())
+
+			// The held interest is posted with the next month once the block is lifted, even without accruals
+			_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+				AccountID: account.ID,
+				Status:    model.AccountStatusActive,
+				Reason:    "changed by test",
+				ChangedBy: account.Owner,
+			})
+			Expect(err).To(BeNil())
+			accountIDs, err := testQueries.ListUnpostedInterestAccounts(context.Background(), ListUnpostedInterestAccountsParams{
+				PeriodStart:    october,
+				PeriodEnd:      october.AddDate(0, 1, 0),
+				AfterAccountID: account.ID - 1,
+				Limit:          1,
+			})
+			Expect(err).To(BeNil())
+			Expect(accountIDs).To(Equal([]int64{account.ID}))
+
+			result, err = store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    october,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Posting.Amount).To(Equal(int64(2)))
+			Expect(result.Posting.Carry).To(BeZero())
+			Expect(result.Account.Balance).To(Equal(int64(2)))
+		})
+
+		It("Test interest expense accounts do not accrue interest", func() {
+			store := NewStore(testDB)
+			account := createRandomAccountWithBalance(0)
+			_, err := accrue(account, september, 1_000_000)
+			Expect(err).To(BeNil())
+
+			result, err := store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september,
+			})
+			Expect(err).To(BeNil())
+			expense := result.Transfer.FromAccount
+
+			accounts, err := testQueries.ListAccountsByTypes(context.Background(), ListAccountsByTypesParams{
+				Types:         []string{expense.Type},
+				CreatedBefore: time.Now().Add(time.Minute),
+				AfterID:       expense.ID - 1,
+				Limit:         1,
+			})
+			Expect(err).To(BeNil())
+			for _, listed := range accounts {
+				Expect(listed.ID).NotTo(Equal(expense.ID))
+			}
+		})
+
+		It("Test list unposted interest accounts", func() {
+			store := NewStore(testDB)
+			account := createRandomAccountWithBalance(0)
+			_, err := accrue(account, september, 1_000_000)
+			Expect(err).To(BeNil())
+
+			listUnposted := func() []int64 {
+				accountIDs, err := testQueries.ListUnpostedInterestAccounts(context.Background(), ListUnpostedInterestAccountsParams{
+					PeriodStart:    september,
+					PeriodEnd:      october,
+					AfterAccountID: account.ID - 1,
+					Limit:          1,
+				})
+				Expect(err).To(BeNil())
+				return accountIDs
+			}
+			Expect(listUnposted()).To(Equal([]int64{account.ID}))
+
+			_, err = store.PostInterestTx(context.Background(), PostInterestTxParams{
+				AccountID: account.ID,
+				Period:    september,
+			})
+			Expect(err).To(BeNil())
+			Expect(listUnposted()).NotTo(ContainElement(account.ID))
+		})
+
+		It("Test accrual runs", func() {
+			day := time.Date(2100, time.January, 2, 0, 0, 0, 0, time.UTC)
+			err := testQueries.CreateInterestAccrualRun(context.Background(), day)
+			Expect(err).To(BeNil())
+			// A day can be recorded again by another accruer
+			err = testQueries.CreateInterestAccrualRun(context.Background(), day)
+			Expect(err).To(BeNil())
+			err = testQueries.CreateInterestAccrualRun(context.Background(), day.AddDate(0, 0, -1))
+			Expect(err).To(BeNil())
+
+			latest, err := testQueries.GetLatestInterestAccrualRun(context.Background())
+			Expect(err).To(BeNil())
+			Expect(latest).To(BeTemporally("==", day))
+		})
+	})
+})
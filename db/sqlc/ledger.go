@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// LedgerSettleDelay is how old a row must be before the ledger verification checks it.
+// Ids are taken when a transaction writes the row, not when it commits, so a recent id can still be followed
+// by a lower one. Leaving the last minute to the next run keeps an incremental run from skipping past it.
+const LedgerSettleDelay = time.Minute
+
+// VerifyLedgerParams contains the input parameters of the ledger verification
+type VerifyLedgerParams struct {
+	// Full verifies the whole ledger instead of only what was written since the latest checkpoint
+	Full bool      `json:"full"`
+	Now  time.Time `json:"now"`
+}
+
+// LedgerReport is the result of the ledger verification
+type LedgerReport struct {
+	// Since is the checkpoint the verification started from, it is nil for a full verification or the first run
+	Since      *LedgerCheckpoint `json:"since"`
+	Checkpoint LedgerCheckpoint  `json:"checkpoint"`
+	// Accounts lists the accounts whose balance is not the sum of their entries
+	Accounts []ListAccountBalanceMismatchesRow `json:"accounts"`
+	// Transfers lists the transfers without exactly one debit and one credit entry matching their amounts
+	Transfers []ListTransferEntryMismatchesRow `json:"transfers"`
+}
+
+// Balanced reports whether the verification found no discrepancy
+func (report LedgerReport) Balanced() bool {
+	return len(report.Accounts) == 0 && len(report.Transfers) == 0
+}
+
+// VerifyLedger checks that the balance of each account is the sum of its entries
+// and that each transfer has a debit and a credit entry matching its amounts.
+// An incremental run only checks the transfers, and the accounts with entries, written since the latest checkpoint,
+// and both kinds of run record a new checkpoint with the number of discrepancies found.
+// The checks read a single snapshot, so transfers committed in the meantime cannot show up as discrepancies.
+func (store SQLStore) VerifyLedger(ctx context.Context, arg VerifyLedgerParams) (LedgerReport, error) {
+	var report LedgerReport
+	err := store.execTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}, func(q *Queries) error {
+		var since LedgerCheckpoint
+		if !arg.Full {
+			checkpoint, err := q.GetLatestLedgerCheckpoint(ctx)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			if err == nil {
+				since = checkpoint
+				report.Since = &checkpoint
+			}
+		}
+
+		mark, err := q.GetLedgerHighWaterMark(ctx, arg.Now.Add(-LedgerSettleDelay))
+		if err != nil {
+			return err
+		}
+		mark.LastAccountID = max(mark.LastAccountID, since.LastAccountID)
+		mark.LastEntryID = max(mark.LastEntryID, since.LastEntryID)
+		mark.LastTransferID = max(mark.LastTransferID, since.LastTransferID)
+
+		report.Accounts, err = q.ListAccountBalanceMismatches(ctx, ListAccountBalanceMismatchesParams{
+			LastAccountID:  mark.LastAccountID,
+			AfterAccountID: since.LastAccountID,
+			AfterEntryID:   since.LastEntryID,
+			LastEntryID:    mark.LastEntryID,
+		})
+		if err != nil {
+			return err
+		}
+
+		report.Transfers, err = q.ListTransferEntryMismatches(ctx, ListTransferEntryMismatchesParams{
+			AfterTransferID: since.LastTransferID,
+			LastTransferID:  mark.LastTransferID,
+		})
+		if err != nil {
+			return err
+		}
+
+		report.Checkpoint, err = q.CreateLedgerCheckpoint(ctx, CreateLedgerCheckpointParams{
+			LastAccountID:         mark.LastAccountID,
+			LastEntryID:           mark.LastEntryID,
+			LastTransferID:        mark.LastTransferID,
+			AccountDiscrepancies:  int32(len(report.Accounts)),
+			TransferDiscrepancies: int32(len(report.Transfers)),
+		})
+		return err
+	})
+
+	return report, err
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	Context("Ledger verification", func() {
+		// Rows written by the test are only verified once they are older than the settle delay
+		verify := func(full bool) LedgerReport {
+			store := NewStore(testDB)
+			report, err := store.VerifyLedger(context.Background(), VerifyLedgerParams{
+				Full: full,
+				Now:  time.Now().Add(LedgerSettleDelay + time.Second),
+			})
+			Expect(err).To(BeNil())
+			return report
+		}
+
+		accountIDs := func(report LedgerReport) []int64 {
+			ids := []int64{}
+			for _, account := range report.Accounts {
+				ids = append(ids, account.AccountID)
+			}
+			return ids
+		}
+
+		transferIDs := func(report LedgerReport) []int64 {
+			ids := []int64{}
+			for _, transfer := range report.Transfers {
+				ids = append(ids, transfer.TransferID)
+			}
+			return ids
+		}
+
+		// createFundedAccount creates an account with an opening entry, so its balance matches its entries
+		createFundedAccount := func(balance int64) Account {
+			account := createRandomAccountWithBalance(balance)
+			_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+				AccountID: account.ID,
+				Amount:    balance,
+			})
+			Expect(err).To(BeNil())
+			return account
+		}
+
+		It("Test transfers keep the ledger balanced", func() {
+			store := NewStore(testDB)
+			account1 := createFundedAccount(100)
+			account2 := createFundedAccount(0)
+
+			result, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        30,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.FromEntry.TransferID.Int64).To(Equal(result.Transfer.ID))
+			Expect(result.ToEntry.TransferID.Int64).To(Equal(result.Transfer.ID))
+
+			report := verify(true)
+			Expect(report.Since).To(BeNil())
+			Expect(accountIDs(report)).NotTo(ContainElements(account1.ID, account2.ID))
+			Expect(transferIDs(report)).NotTo(ContainElement(result.Transfer.ID))
+			Expect(report.Checkpoint.LastTransferID).To(BeNumerically(">=", result.Transfer.ID))
+		})
+
+		It("Test balance without entries is reported", func() {
+			account := createFundedAccount(10)
+			_, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+				Amount: 5,
+				ID:     account.ID,
+			})
+			Expect(err).To(BeNil())
+
+			report := verify(true)
+			Expect(report.Balanced()).To(BeFalse())
+			for _, mismatch := range report.Accounts {
+				if mismatch.AccountID == account.ID {
+					Expect(mismatch.Balance).To(Equal(int64(15)))
+					Expect(mismatch.EntriesTotal).To(Equal(int64(10)))
+				}
+			}
+			Expect(accountIDs(report)).To(ContainElement(account.ID))
+		})
+
+		It("Test incremental verification resumes from the checkpoint", func() {
+			account1 := createFundedAccount(100)
+			account2 := createFundedAccount(0)
+
+			first := verify(true)
+
+			// A transfer without entries is unbalanced
+			transfer, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        10,
+				ToAmount:      10,
+				ExchangeRate:  "1",
+				Metadata:      json.RawMessage(`{}`),
+			})
+			Expect(err).To(BeNil())
+
+			second := verify(false)
+			Expect(second.Since).NotTo(BeNil())
+			Expect(second.Since.ID).To(Equal(first.Checkpoint.ID))
+			Expect(transferIDs(second)).To(ConsistOf(transfer.ID))
+			Expect(second.Transfers[0].EntryCount).To(Equal(int32(0)))
+			Expect(second.Checkpoint.TransferDiscrepancies).To(Equal(int32(1)))
+
+			// The next run only looks at what was written after the second checkpoint
+			third := verify(false)
+			Expect(third.Since.ID).To(Equal(second.Checkpoint.ID))
+			Expect(transferIDs(third)).To(BeEmpty())
+		})
+	})
+})
@@ -3,7 +3,11 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Account struct {
@@ -12,6 +16,41 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// how far below zero the balance may go
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// set when the account is closed, closed accounts keep their history
+	ClosedAt sql.NullTime `json:"closed_at"`
+	// active, frozen or closed, frozen accounts cannot be debited
+	Status string `json:"status"`
+	// frozen accounts cannot be credited either when set
+	BlockCredits bool `json:"block_credits"`
+	// checking, savings or loan
+	Type string `json:"type"`
+	// optional, unique among the accounts of the owner
+	Nickname string `json:"nickname"`
+}
+
+type AccountStatusChange struct {
+	ID           int64     `json:"id"`
+	AccountID    int64     `json:"account_id"`
+	FromStatus   string    `json:"from_status"`
+	ToStatus     string    `json:"to_status"`
+	BlockCredits bool      `json:"block_credits"`
+	Reason       string    `json:"reason"`
+	ChangedBy    string    `json:"changed_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Currency struct {
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+	// digits of the minor unit, amounts are stored in minor units
+	Exponent int32  `json:"exponent"`
+	Symbol   string `json:"symbol"`
+	// disabled currencies are kept for existing accounts but cannot be used in new requests
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Entry struct {
@@ -20,15 +59,164 @@ type Entry struct {
 	// can be negative
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// the transfer that wrote the entry
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	// copied from the transfer that wrote the entry
+	Description string `json:"description"`
+	// copied from the transfer that wrote the entry
+	ExternalReference string `json:"external_reference"`
+}
+
+type Hold struct {
+	ID          int64 `json:"id"`
+	AccountID   int64 `json:"account_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	// reserved amount, must be positive
+	Amount int64  `json:"amount"`
+	Status string `json:"status"`
+	// set when the hold is captured
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type InterestAccrual struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	// end of day balance the interest accrued on
+	Balance int64 `json:"balance"`
+	// annual rate of the account type on the accrual date
+	AnnualRate string `json:"annual_rate"`
+	// in millionths of the minor unit, rounded half to even
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InterestAccrualRun struct {
+	// day whose interest was accrued for every account, accruers resume after the latest one
+	AccrualDate time.Time `json:"accrual_date"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type InterestExpenseAccount struct {
+	Currency string `json:"currency"`
+	// account of the bank that pays and receives the interest in the currency
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InterestPosting struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// first day of the month the posted interest accrued in
+	Period time.Time `json:"period"`
+	// accruals of the period plus the previous carry, in millionths of the minor unit
+	Accrued int64 `json:"accrued"`
+	// posted in minor units, truncated toward zero
+	Amount int64 `json:"amount"`
+	// remainder carried to the next posting, in millionths of the minor unit
+	Carry int64 `json:"carry"`
+	// set when a whole minor unit was posted
+	EntryID   sql.NullInt64 `json:"entry_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	// set when a whole minor unit was posted, the transfer from or to the interest expense account
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type LedgerCheckpoint struct {
+	ID            int64 `json:"id"`
+	LastAccountID int64 `json:"last_account_id"`
+	// entries up to this id have been verified
+	LastEntryID int64 `json:"last_entry_id"`
+	// transfers up to this id have been verified
+	LastTransferID        int64     `json:"last_transfer_id"`
+	AccountDiscrepancies  int32     `json:"account_discrepancies"`
+	TransferDiscrepancies int32     `json:"transfer_discrepancies"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type RevokedToken struct {
+	// ID of the token payload
+	ID        uuid.UUID `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64        `json:"id"`
+	Owner         string       `json:"owner"`
+	FromAccountID int64        `json:"from_account_id"`
+	ToAccountID   int64        `json:"to_account_id"`
+	Amount        int64        `json:"amount"`
+	Frequency     string       `json:"frequency"`
+	StartAt       time.Time    `json:"start_at"`
+	EndAt         sql.NullTime `json:"end_at"`
+	// time of the next occurrence of the schedule
+	NextRunAt time.Time `json:"next_run_at"`
+	// set while a failed occurrence waits for a retry
+	RetryAt sql.NullTime `json:"retry_at"`
+	// failed attempts of the next occurrence
+	Attempts int32 `json:"attempts"`
+	// number of the next occurrence, counted from start_at
+	Occurrences int32     `json:"occurrences"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time `json:"scheduled_for"`
+	Attempt             int32     `json:"attempt"`
+	Outcome             string    `json:"outcome"`
+	// set when the run succeeded
+	TransferID    sql.NullInt64 `json:"transfer_id"`
+	FailureReason string        `json:"failure_reason"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+type Session struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	RefreshToken string    `json:"refresh_token"`
+	UserAgent    string    `json:"user_agent"`
+	ClientIp     string    `json:"client_ip"`
+	IsBlocked    bool      `json:"is_blocked"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
-	// must be postive
+	// must be postive, in the currency of the source account
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// amount credited, in the currency of the destination account
+	ToAmount int64 `json:"to_amount"`
+	// rate applied to amount to get to_amount
+	ExchangeRate string `json:"exchange_rate"`
+	// set on a reversal, the transfer it refunds
+	ReversalOf sql.NullInt64 `json:"reversal_of"`
+	// free text memo set by the payer
+	Description string `json:"description"`
+	// reference of the payment in the system of the payer, e.g. an invoice number
+	ExternalReference string          `json:"external_reference"`
+	Metadata          json.RawMessage `json:"metadata"`
+}
+
+type TransferIdempotencyKey struct {
+	Key string `json:"key"`
+	// hash of the transfer parameters the key was first used with
+	RequestHash string `json:"request_hash"`
+	TransferID  int64  `json:"transfer_id"`
+	// transfer result returned to replays of the key
+	Result    json.RawMessage `json:"result"`
+	CreatedAt time.Time       `json:"created_at"`
+	// keys are scoped to the source account, so clients of different owners cannot collide
+	FromAccountID int64 `json:"from_account_id"`
 }
 
 type User struct {
@@ -38,4 +226,6 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	// customer, teller or admin
+	Role string `json:"role"`
 }
@@ -4,24 +4,86 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	CountAccounts(ctx context.Context, owner string) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (TransferIdempotencyKey, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error)
+	CreateInterestAccrualRun(ctx context.Context, accrualDate time.Time) error
+	CreateInterestExpenseAccount(ctx context.Context, arg CreateInterestExpenseAccountParams) (InterestExpenseAccount, error)
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error)
+	CreateLedgerCheckpoint(ctx context.Context, arg CreateLedgerCheckpointParams) (LedgerCheckpoint, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUsers(ctx context.Context, arg CreateUsersParams) (User, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (TransferIdempotencyKey, error)
+	GetInterestExpenseAccount(ctx context.Context, currency string) (Account, error)
+	GetInterestPosting(ctx context.Context, arg GetInterestPostingParams) (InterestPosting, error)
+	GetLatestInterestAccrualRun(ctx context.Context) (time.Time, error)
+	GetLatestInterestPosting(ctx context.Context, arg GetLatestInterestPostingParams) (InterestPosting, error)
+	GetLatestLedgerCheckpoint(ctx context.Context) (LedgerCheckpoint, error)
+	GetLedgerHighWaterMark(ctx context.Context, settledBefore time.Time) (GetLedgerHighWaterMarkRow, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	IsTokenRevoked(ctx context.Context, id uuid.UUID) (bool, error)
+	ListAccountBalanceMismatches(ctx context.Context, arg ListAccountBalanceMismatchesParams) ([]ListAccountBalanceMismatchesRow, error)
+	ListAccountStatusChanges(ctx context.Context, arg ListAccountStatusChangesParams) ([]AccountStatusChange, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsByTypes(ctx context.Context, arg ListAccountsByTypesParams) ([]Account, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	ListDueScheduledTransfers(ctx context.Context, arg ListDueScheduledTransfersParams) ([]ScheduledTransfer, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entry, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListTransferEntryMismatches(ctx context.Context, arg ListTransferEntryMismatchesParams) ([]ListTransferEntryMismatchesRow, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListUnpostedInterestAccounts(ctx context.Context, arg ListUnpostedInterestAccountsParams) ([]int64, error)
+	LockIdempotencyKey(ctx context.Context, arg LockIdempotencyKeyParams) error
+	LockInterestExpenseAccount(ctx context.Context, currency string) error
+	MarkHoldCaptured(ctx context.Context, arg MarkHoldCapturedParams) (Hold, error)
+	MarkHoldReleased(ctx context.Context, id int64) (Hold, error)
+	ReleaseExpiredHolds(ctx context.Context) (int64, error)
+	RevokeToken(ctx context.Context, arg RevokeTokenParams) error
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error)
+	SumActiveHolds(ctx context.Context, accountID int64) (int64, error)
+	SumActiveHoldsByAccounts(ctx context.Context, accountIds []int64) ([]SumActiveHoldsByAccountsRow, error)
+	SumEntriesBefore(ctx context.Context, arg SumEntriesBeforeParams) (int64, error)
+	SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error)
+	SumInterestAccruals(ctx context.Context, arg SumInterestAccrualsParams) (int64, error)
+	SumTransferReversals(ctx context.Context, reversalOf sql.NullInt64) (int64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
+	UpdateScheduledTransferState(ctx context.Context, arg UpdateScheduledTransferStateParams) (ScheduledTransfer, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpsertCurrency(ctx context.Context, arg UpsertCurrencyParams) (Currency, error)
 }
 
 var _ Querier = (*Queries)(nil)
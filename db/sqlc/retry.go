@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// MaxTxAttempts is how many times a transaction is run before its serialization failure or deadlock is returned
+	MaxTxAttempts = 5
+	// txRetryBaseDelay is the backoff before the first retry, it doubles with every retry
+	txRetryBaseDelay = 10 * time.Millisecond
+)
+
+// Keys of the txRetries metric. Retries are counted by the error that caused them,
+// and transactions that still failed after MaxTxAttempts are counted as exhausted.
+const (
+	txRetriesSerializationFailure = "serialization_failure"
+	txRetriesDeadlockDetected     = "deadlock_detected"
+	txRetriesExhausted            = "exhausted"
+)
+
+// txRetries counts the transaction retries, it is published under db_tx_retries with the other expvar metrics
+var txRetries = expvar.NewMap("db_tx_retries")
+
+// txIsolationKey is the context key of the isolation level set by WithTxIsolation
+type txIsolationKey struct{}
+
+// WithTxIsolation returns a context that runs the store transactions started with it at the given isolation level,
+// unless a transaction asks for a level of its own
+func WithTxIsolation(ctx context.Context, level sql.IsolationLevel) context.Context {
+	return context.WithValue(ctx, txIsolationKey{}, level)
+}
+
+// txOptions returns the options with the isolation level of the context when they don't set one
+func txOptions(ctx context.Context, opts *sql.TxOptions) *sql.TxOptions {
+	level, ok := ctx.Value(txIsolationKey{}).(sql.IsolationLevel)
+	if !ok || (opts != nil && opts.Isolation != sql.LevelDefault) {
+		return opts
+	}
+
+	withLevel := sql.TxOptions{Isolation: level}
+	if opts != nil {
+		withLevel.ReadOnly = opts.ReadOnly
+	}
+	return &withLevel
+}
+
+// retryableTxError reports whether the transaction failed with an error that running it again can resolve,
+// and returns the name of the error
+func retryableTxError(err error) (string, bool) {
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		switch name := pqError.Code.Name(); name {
+		case txRetriesSerializationFailure, txRetriesDeadlockDetected:
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// txRetryDelay returns a random delay up to the exponential backoff of the retry,
+// so transactions that conflicted with each other don't run into each other again
+func txRetryDelay(retry int) time.Duration {
+	backoff := txRetryBaseDelay << (retry - 1)
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// txRetryCount returns the value of a key of the txRetries metric
+func txRetryCount(key string) int64 {
+	count, ok := txRetries.Get(key).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return count.Value()
+}
+
+var _ = Describe("Operation", func() {
+	Context("Transaction retries", func() {
+		It("Test serialization failure is retried", func() {
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+			retries := txRetryCount(txRetriesSerializationFailure)
+
+			injector := &faultInjector{failAt: 3, code: "40001", faultyTxs: 1}
+			result, err := injector.store(testDB).TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        10,
+			})
+			Expect(err).To(BeNil())
+			Expect(injector.txs).To(HaveLen(2))
+			Expect(result.FromAccount.Balance).To(Equal(account1.Balance - 10))
+			Expect(txRetryCount(txRetriesSerializationFailure)).To(Equal(retries + 1))
+		})
+
+		It("Test deadlock retries are bounded", func() {
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+			exhausted := txRetryCount(txRetriesExhausted)
+
+			injector := &faultInjector{failAt: 1, code: "40P01"}
+			_, err := injector.store(testDB).TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        10,
+			})
+			var pqError *pq.Error
+			Expect(errors.As(err, &pqError)).To(BeTrue())
+			Expect(pqError.Code.Name()).To(Equal(txRetriesDeadlockDetected))
+			Expect(injector.txs).To(HaveLen(MaxTxAttempts))
+			Expect(txRetryCount(txRetriesExhausted)).To(Equal(exhausted + 1))
+		})
+
+		It("Test other errors are not retried", func() {
+			injector := &faultInjector{failAt: 1}
+			_, err := injector.store(testDB).TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: createRandomAccount().ID,
+				ToAccountID:   createRandomAccount().ID,
+				Amount:        10,
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(injector.txs).To(HaveLen(1))
+		})
+
+		It("Test isolation level from the context", func() {
+			store := NewStore(testDB)
+			ctx := WithTxIsolation(context.Background(), sql.LevelSerializable)
+
+			var level string
+			err := store.(SQLStore).ExecTx(ctx, func(q *Queries) error {
+				return q.db.QueryRowContext(ctx, "SHOW transaction_isolation").Scan(&level)
+			})
+			Expect(err).To(BeNil())
+			Expect(level).To(Equal("serializable"))
+
+			// A transaction asking for a level of its own keeps it
+			err = store.(SQLStore).ExecTxWithOptions(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}, func(q *Queries) error {
+				return q.db.QueryRowContext(ctx, "SHOW transaction_isolation").Scan(&level)
+			})
+			Expect(err).To(BeNil())
+			Expect(level).To(Equal("repeatable read"))
+		})
+
+		for _, level := range []sql.IsolationLevel{sql.LevelReadCommitted, sql.LevelSerializable} {
+			level := level
+
+			It("Test opposing transfers at "+level.String()+" isolation", func() {
+				store := NewStore(testDB)
+				ctx := WithTxIsolation(context.Background(), level)
+				account1 := createRandomAccountWithBalance(10000)
+				account2 := createRandomAccountWithBalance(10000)
+
+				workers := 20
+				transfersPerWorker := 10
+				amount := int64(1)
+
+				type outcome struct {
+					forward bool
+					err     error
+				}
+				outcomes := make(chan outcome)
+
+				for i := 0; i < workers; i++ {
+					go func(worker int) {
+						for j := 0; j < transfersPerWorker; j++ {
+							// Half of the transfers go the other way, so the accounts are locked in both orders
+							forward := (worker+j)%2 == 0
+							arg := TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: amount}
+							if !forward {
+								arg.FromAccountID, arg.ToAccountID = arg.ToAccountID, arg.FromAccountID
+							}
+							_, err := store.TransferTx(ctx, arg)
+							outcomes <- outcome{forward: forward, err: err}
+						}
+					}(i)
+				}
+
+				moved := int64(0)
+				succeeded := 0
+				for i := 0; i < workers*transfersPerWorker; i++ {
+					result := <-outcomes
+					if result.err != nil {
+						// Only a conflict that outlasted every retry can fail a transfer
+						_, retryable := retryableTxError(result.err)
+						Expect(retryable).To(BeTrue(), "%v", result.err)
+						continue
+					}
+					succeeded++
+					if result.forward {
+						moved += amount
+					} else {
+						moved -= amount
+					}
+				}
+				Expect(succeeded).To(BeNumerically(">", 0))
+				if level == sql.LevelReadCommitted {
+					Expect(succeeded).To(Equal(workers * transfersPerWorker))
+				}
+
+				updated1, err := testQueries.GetAccount(context.Background(), account1.ID)
+				Expect(err).To(BeNil())
+				updated2, err := testQueries.GetAccount(context.Background(), account2.ID)
+				Expect(err).To(BeNil())
+				Expect(updated1.Balance).To(Equal(account1.Balance - moved))
+				Expect(updated2.Balance).To(Equal(account2.Balance + moved))
+			})
+		}
+	})
+})
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Petatron/bank-simulator-backend/exchange"
+)
+
+var (
+	// ErrTransferAlreadyReversed is returned when a transfer has already been refunded in full
+	ErrTransferAlreadyReversed = errors.New("transfer is already reversed")
+	// ErrReversalExceedsTransfer is returned when a refund would take the refunded total above the original amount
+	ErrReversalExceedsTransfer = errors.New("reversal exceeds the amount left to refund")
+	// ErrInvalidReversal is returned when a transfer cannot be reversed, such as a reversal itself
+	ErrInvalidReversal = errors.New("transfer cannot be reversed")
+)
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount is optional. It is the refund in the currency of the original source account,
+	// zero refunds whatever is left of the original amount.
+	Amount int64 `json:"amount"`
+}
+
+// ReverseTransferTxResult is the result of the reverse transfer transaction
+type ReverseTransferTxResult struct {
+	OriginalTransfer Transfer         `json:"original_transfer"`
+	Reversal         TransferTxResult `json:"reversal"`
+	// RefundedAmount is the total refunded on the original transfer, this reversal included
+	RefundedAmount int64 `json:"refunded_amount"`
+}
+
+// ReverseTransferTx refunds a transfer in full or in part with a compensating transfer in the opposite direction.
+// The reversal is linked to the original transfer, and the refunds of a transfer are capped at its amount.
+// Concurrent reversals of the same transfer are serialized on the original transfer row.
+// It returns ErrTransferAlreadyReversed if nothing is left to refund, ErrReversalExceedsTransfer if the amount
+// is more than what is left, and the errors of TransferTx if an account cannot be debited or credited,
+// including ErrInsufficientFunds if the refund would spend funds the recipient has on hold.
+func (store SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if original.ReversalOf.Valid {
+			return fmt.Errorf("%w: transfer %d is a reversal", ErrInvalidReversal, original.ID)
+		}
+		result.OriginalTransfer = original
+
+		refunded, err := q.SumTransferReversals(ctx, sql.NullInt64{Int64: original.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+		remaining := original.Amount - refunded
+		if remaining <= 0 {
+			return fmt.Errorf("%w: transfer %d", ErrTransferAlreadyReversed, original.ID)
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount < 0 || amount > remaining {
+			return fmt.Errorf("%w: %d of %d left on transfer %d", ErrReversalExceedsTransfer, amount, remaining, original.ID)
+		}
+
+		// The refund moves back the share of the credited amount that matches the refunded share of the amount,
+		// computed on the running total so partial refunds add up to the full credited amount
+		debit := proportionalAmount(refunded+amount, original) - proportionalAmount(refunded, original)
+		if debit <= 0 {
+			return fmt.Errorf("%w: refund of %d is too small to convert", ErrInvalidReversal, amount)
+		}
+
+		rate, err := exchange.ParseRate(original.ExchangeRate)
+		if err != nil {
+			return err
+		}
+
+		result.Reversal, err = transfer(ctx, q, CreateTransferParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        debit,
+			ToAmount:      amount,
+			ExchangeRate:  rate.Inverse().String(),
+			ReversalOf:    sql.NullInt64{Int64: original.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// The recipient may have put the funds on hold since, and a refund cannot spend them either
+		err = checkAvailableBalance(ctx, q, result.Reversal.FromAccount)
+		if err != nil {
+			return err
+		}
+
+		result.RefundedAmount = refunded + amount
+		return nil
+	})
+
+	return result, err
+}
+
+// proportionalAmount returns the share of the credited amount of the transfer that matches amount, rounded down
+func proportionalAmount(amount int64, original Transfer) int64 {
+	share := new(big.Int).Mul(big.NewInt(amount), big.NewInt(original.ToAmount))
+	return share.Quo(share, big.NewInt(original.Amount)).Int64()
+}
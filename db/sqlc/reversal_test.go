@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	Context("Transfer reversals", func() {
+		It("Test full reversal", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+
+			original, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        60,
+			})
+			Expect(err).To(BeNil())
+
+			result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: original.Transfer.ID,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.RefundedAmount).To(Equal(int64(60)))
+			Expect(result.Reversal.Transfer.ReversalOf.Int64).To(Equal(original.Transfer.ID))
+			Expect(result.Reversal.Transfer.FromAccountID).To(Equal(account2.ID))
+			Expect(result.Reversal.Transfer.ToAccountID).To(Equal(account1.ID))
+			Expect(result.Reversal.FromEntry.Amount).To(Equal(int64(-60)))
+			Expect(result.Reversal.ToEntry.Amount).To(Equal(int64(60)))
+			Expect(result.Reversal.FromAccount.Balance).To(Equal(int64(0)))
+			Expect(result.Reversal.ToAccount.Balance).To(Equal(int64(100)))
+
+			_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: original.Transfer.ID,
+			})
+			Expect(errors.Is(err, ErrTransferAlreadyReversed)).To(BeTrue())
+
+			// A reversal cannot be reversed itself
+			_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: result.Reversal.Transfer.ID,
+			})
+			Expect(errors.Is(err, ErrInvalidReversal)).To(BeTrue())
+		})
+
+		It("Test reversal cannot spend funds on hold", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+			account3 := createRandomAccountWithBalance(0)
+
+			original, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        60,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = store.PlaceHold(context.Background(), PlaceHoldParams{
+				AccountID:   account2.ID,
+				ToAccountID: account3.ID,
+				Amount:      50,
+				ExpiresAt:   time.Now().Add(time.Hour),
+			})
+			Expect(err).To(BeNil())
+
+			_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: original.Transfer.ID,
+			})
+			Expect(errors.Is(err, ErrInsufficientFunds)).To(BeTrue())
+
+			// What is not on hold can still be refunded
+			result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: original.Transfer.ID,
+				Amount:     10,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Reversal.FromAccount.Balance).To(Equal(int64(50)))
+		})
+
+		It("Test partial refunds are capped at the original amount", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+
+			original, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        60,
+			})
+			Expect(err).To(BeNil())
+
+			result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: original.Transfer.ID,
+				Amount:     25,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.RefundedAmount).To(Equal(int64(25)))
+
+			_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: original.Transfer.ID,
+				Amount:     40,
+			})
+			Expect(errors.Is(err, ErrReversalExceedsTransfer)).To(BeTrue())
+
+			result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: original.Transfer.ID,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Reversal.Transfer.Amount).To(Equal(int64(35)))
+			Expect(result.RefundedAmount).To(Equal(int64(60)))
+		})
+
+		It("Test partial refunds of a cross-currency transfer add up", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+
+			original, err := store.FXTransferTx(context.Background(), FXTransferTxParams{
+				TransferTxParams: TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        10,
+				},
+				ToAmount:     9,
+				ExchangeRate: "0.9",
+			})
+			Expect(err).To(BeNil())
+
+			var debited int64
+			for i := 0; i < 2; i++ {
+				result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+					TransferID: original.Transfer.ID,
+					Amount:     5,
+				})
+				Expect(err).To(BeNil())
+				Expect(result.Reversal.Transfer.ToAmount).To(Equal(int64(5)))
+				debited += result.Reversal.Transfer.Amount
+			}
+			Expect(debited).To(Equal(int64(9)))
+
+			account, err := testQueries.GetAccount(context.Background(), account2.ID)
+			Expect(err).To(BeNil())
+			Expect(account.Balance).To(BeZero())
+		})
+
+		It("Test concurrent reversals cannot refund twice", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+
+			original, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        60,
+			})
+			Expect(err).To(BeNil())
+
+			n := 5
+			errs := make(chan error, n)
+			var wg sync.WaitGroup
+			for i := 0; i < n; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+						TransferID: original.Transfer.ID,
+					})
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			succeeded := 0
+			for err := range errs {
+				if err == nil {
+					succeeded++
+					continue
+				}
+				Expect(errors.Is(err, ErrTransferAlreadyReversed)).To(BeTrue())
+			}
+			Expect(succeeded).To(Equal(1))
+
+			refunded, err := testQueries.SumTransferReversals(context.Background(), sql.NullInt64{Int64: original.Transfer.ID, Valid: true})
+			Expect(err).To(BeNil())
+			Expect(refunded).To(Equal(int64(60)))
+		})
+	})
+})
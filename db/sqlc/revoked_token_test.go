@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL Revoked Token Operations", func() {
+	It("Test RevokeToken", func() {
+		id := uuid.New()
+
+		revoked, err := testQueries.IsTokenRevoked(context.Background(), id)
+		Expect(err).To(BeNil())
+		Expect(revoked).To(BeFalse())
+
+		arg := RevokeTokenParams{ID: id, ExpiresAt: time.Now().Add(time.Hour)}
+		Expect(testQueries.RevokeToken(context.Background(), arg)).To(Succeed())
+		// Revoking the same token twice is not an error
+		Expect(testQueries.RevokeToken(context.Background(), arg)).To(Succeed())
+
+		revoked, err = testQueries.IsTokenRevoked(context.Background(), id)
+		Expect(err).To(BeNil())
+		Expect(revoked).To(BeTrue())
+	})
+
+	It("Test DeleteExpiredRevokedTokens", func() {
+		expired := uuid.New()
+		active := uuid.New()
+
+		err := testQueries.RevokeToken(context.Background(), RevokeTokenParams{ID: expired, ExpiresAt: time.Now().Add(-time.Hour)})
+		Expect(err).To(BeNil())
+		err = testQueries.RevokeToken(context.Background(), RevokeTokenParams{ID: active, ExpiresAt: time.Now().Add(time.Hour)})
+		Expect(err).To(BeNil())
+
+		deleted, err := testQueries.DeleteExpiredRevokedTokens(context.Background())
+		Expect(err).To(BeNil())
+		Expect(deleted).To(BeNumerically(">=", 1))
+
+		revoked, err := testQueries.IsTokenRevoked(context.Background(), expired)
+		Expect(err).To(BeNil())
+		Expect(revoked).To(BeFalse())
+
+		revoked, err = testQueries.IsTokenRevoked(context.Background(), active)
+		Expect(err).To(BeNil())
+		Expect(revoked).To(BeTrue())
+	})
+})
@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+)
+
+const (
+	// ScheduledTransferStatusActive is the status of a schedule that still runs
+	ScheduledTransferStatusActive = "active"
+	// ScheduledTransferStatusPaused is the status of a schedule that is skipped until it is resumed
+	ScheduledTransferStatusPaused = "paused"
+	// ScheduledTransferStatusCompleted is the status of a schedule that has no further occurrence
+	ScheduledTransferStatusCompleted = "completed"
+	// ScheduledTransferStatusCancelled is the status of a schedule that was cancelled by its owner
+	ScheduledTransferStatusCancelled = "cancelled"
+)
+
+const (
+	// ScheduledTransferRunSucceeded is the outcome of a run that made the transfer
+	ScheduledTransferRunSucceeded = "succeeded"
+	// ScheduledTransferRunSkipped is the outcome of a run that was skipped for insufficient funds
+	ScheduledTransferRunSkipped = "skipped"
+	// ScheduledTransferRunFailed is the outcome of a run that failed for any other reason
+	ScheduledTransferRunFailed = "failed"
+)
+
+const (
+	// MaxScheduledTransferAttempts is how many times an occurrence is attempted before it is given up
+	MaxScheduledTransferAttempts = 3
+	// ScheduledTransferRetryDelay is the delay before the first retry, it grows with every failed attempt
+	ScheduledTransferRetryDelay = 5 * time.Minute
+)
+
+// ErrScheduledTransferNotDue is returned when a scheduled transfer is executed before it is due or while it is not active
+var ErrScheduledTransferNotDue = errors.New("scheduled transfer is not due")
+
+// ExecuteScheduledTransferTxParams contains the input parameters of the scheduled transfer execution
+type ExecuteScheduledTransferTxParams struct {
+	ID  int64     `json:"id"`
+	Now time.Time `json:"now"`
+}
+
+// ExecuteScheduledTransferTxResult is the result of the scheduled transfer execution
+type ExecuteScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRun `json:"run"`
+	// Transfer is only set when the run succeeded
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// ExecuteScheduledTransferTx runs the due occurrence of a scheduled transfer like TransferTx and records the run.
+// A run that succeeds, or is skipped for insufficient funds, moves the schedule to its next occurrence after now,
+// so missed occurrences are not caught up. Other failures are retried with a growing delay,
+// and the occurrence is given up after MaxScheduledTransferAttempts.
+// It returns ErrScheduledTransferNotDue if the schedule is not active or not due at now.
+func (store SQLStore) ExecuteScheduledTransferTx(ctx context.Context, arg ExecuteScheduledTransferTxParams) (ExecuteScheduledTransferTxResult, error) {
+	var result ExecuteScheduledTransferTxResult
+	var transferErr error
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		// The transaction can be retried, so a failure of an earlier attempt must not stick
+		transferErr = nil
+		scheduled, err := q.GetScheduledTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkScheduledTransferDue(scheduled, arg.Now); err != nil {
+			return err
+		}
+
+		transferResult, err := transferTx(ctx, q, FXTransferTxParams{
+			TransferTxParams: TransferTxParams{
+				FromAccountID: scheduled.FromAccountID,
+				ToAccountID:   scheduled.ToAccountID,
+				Amount:        scheduled.Amount,
+			},
+			ToAmount:     scheduled.Amount,
+			ExchangeRate: "1",
+		})
+		if err != nil {
+			transferErr = err
+			return err
+		}
+		result.Transfer = &transferResult
+
+		transferID := sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true}
+		result.Run, result.ScheduledTransfer, err = recordScheduledTransferRun(ctx, q, scheduled, arg.Now, transferID, nil)
+		return err
+	})
+	if transferErr == nil {
+		return result, err
+	}
+
+	// The failed transfer was rolled back, so the run is recorded in a transaction of its own
+	err = store.ExecTx(ctx, func(q *Queries) error {
+		scheduled, err := q.GetScheduledTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkScheduledTransferDue(scheduled, arg.Now); err != nil {
+			return err
+		}
+
+		result.Run, result.ScheduledTransfer, err = recordScheduledTransferRun(ctx, q, scheduled, arg.Now, sql.NullInt64{}, transferErr)
+		return err
+	})
+
+	return result, err
+}
+
+// checkScheduledTransferDue checks if the scheduled transfer is active and due at now
+func checkScheduledTransferDue(scheduled ScheduledTransfer, now time.Time) error {
+	dueAt := scheduled.NextRunAt
+	if scheduled.RetryAt.Valid {
+		dueAt = scheduled.RetryAt.Time
+	}
+	if scheduled.Status != ScheduledTransferStatusActive || dueAt.After(now) {
+		return fmt.Errorf("%w: scheduled transfer %d", ErrScheduledTransferNotDue, scheduled.ID)
+	}
+	return nil
+}
+
+// recordScheduledTransferRun records the outcome of a run and moves the schedule to its next attempt or occurrence
+func recordScheduledTransferRun(
+	ctx context.Context,
+	q *Queries,
+	scheduled ScheduledTransfer,
+	now time.Time,
+	transferID sql.NullInt64,
+	transferErr error) (ScheduledTransferRun, ScheduledTransfer, error) {
+
+	attempt := scheduled.Attempts + 1
+	outcome := ScheduledTransferRunSucceeded
+	failureReason := ""
+	switch {
+	case errors.Is(transferErr, ErrInsufficientFunds):
+		outcome = ScheduledTransferRunSkipped
+		failureReason = transferErr.Error()
+	case transferErr != nil:
+		outcome = ScheduledTransferRunFailed
+		failureReason = transferErr.Error()
+	}
+
+	run, err := q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+		ScheduledTransferID: scheduled.ID,
+		ScheduledFor:        scheduled.NextRunAt,
+		Attempt:             attempt,
+		Outcome:             outcome,
+		TransferID:          transferID,
+		FailureReason:       failureReason,
+	})
+	if err != nil {
+		return run, scheduled, err
+	}
+
+	state := UpdateScheduledTransferStateParams{
+		ID:          scheduled.ID,
+		Status:      ScheduledTransferStatusActive,
+		NextRunAt:   scheduled.NextRunAt,
+		Attempts:    0,
+		Occurrences: scheduled.Occurrences,
+	}
+	if outcome == ScheduledTransferRunFailed && attempt < MaxScheduledTransferAttempts {
+		state.Attempts = attempt
+		state.RetryAt = sql.NullTime{Time: now.Add(time.Duration(attempt) * ScheduledTransferRetryDelay), Valid: true}
+	} else {
+		occurrences, nextRunAt, ok := nextOccurrence(scheduled, now)
+		if ok {
+			state.Occurrences = occurrences
+			state.NextRunAt = nextRunAt
+		} else {
+			state.Status = ScheduledTransferStatusCompleted
+		}
+	}
+
+	scheduled, err = q.UpdateScheduledTransferState(ctx, state)
+	return run, scheduled, err
+}
+
+// nextOccurrence returns the number and time of the first occurrence of the schedule after now.
+// It returns false if the schedule has no further occurrence before its end.
+func nextOccurrence(scheduled ScheduledTransfer, now time.Time) (int32, time.Time, bool) {
+	frequency := model.ScheduleFrequency(scheduled.Frequency)
+	for n := scheduled.Occurrences + 1; ; n++ {
+		occurrence, ok := frequency.Occurrence(scheduled.StartAt, int(n))
+		if !ok || (scheduled.EndAt.Valid && occurrence.After(scheduled.EndAt.Time)) {
+			return n, time.Time{}, false
+		}
+		if occurrence.After(now) {
+			return n, occurrence, true
+		}
+	}
+}
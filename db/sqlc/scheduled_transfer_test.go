@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	Context("Scheduled transfers", func() {
+		createScheduledTransfer := func(from, to Account, amount int64, frequency model.ScheduleFrequency, startAt time.Time) ScheduledTransfer {
+			scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+				Owner:         from.Owner,
+				FromAccountID: from.ID,
+				ToAccountID:   to.ID,
+				Amount:        amount,
+				Frequency:     string(frequency),
+				StartAt:       startAt,
+				NextRunAt:     startAt,
+			})
+			Expect(err).To(BeNil())
+			Expect(scheduled.Status).To(Equal(ScheduledTransferStatusActive))
+			Expect(scheduled.Occurrences).To(BeZero())
+			return scheduled
+		}
+
+		It("Test execute recurring scheduled transfer", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+			startAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+			scheduled := createScheduledTransfer(account1, account2, 30, model.ScheduleFrequencyMonthly, startAt)
+
+			result, err := store.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+				ID:  scheduled.ID,
+				Now: time.Now(),
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Transfer).NotTo(BeNil())
+			Expect(result.Transfer.FromAccount.Balance).To(Equal(int64(70)))
+			Expect(result.Run.Outcome).To(Equal(ScheduledTransferRunSucceeded))
+			Expect(result.Run.TransferID.Int64).To(Equal(result.Transfer.Transfer.ID))
+			Expect(result.ScheduledTransfer.Occurrences).To(Equal(int32(1)))
+			nextRunAt, _ := model.ScheduleFrequencyMonthly.Occurrence(startAt, 1)
+			Expect(result.ScheduledTransfer.NextRunAt).To(BeTemporally("==", nextRunAt))
+
+			// The next occurrence is not due yet
+			_, err = store.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+				ID:  scheduled.ID,
+				Now: time.Now(),
+			})
+			Expect(errors.Is(err, ErrScheduledTransferNotDue)).To(BeTrue())
+		})
+
+		It("Test once scheduled transfer completes", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+			scheduled := createScheduledTransfer(account1, account2, 30, model.ScheduleFrequencyOnce, time.Now().Add(-time.Minute))
+
+			result, err := store.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+				ID:  scheduled.ID,
+				Now: time.Now(),
+			})
+			Expect(err).To(BeNil())
+			Expect(result.ScheduledTransfer.Status).To(Equal(ScheduledTransferStatusCompleted))
+
+			due, err := testQueries.ListDueScheduledTransfers(context.Background(), ListDueScheduledTransfersParams{
+				Now:        time.Now(),
+				LimitCount: 1000,
+			})
+			Expect(err).To(BeNil())
+			for _, item := range due {
+				Expect(item.ID).NotTo(Equal(scheduled.ID))
+			}
+		})
+
+		It("Test scheduled transfer is skipped on insufficient funds", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(10)
+			account2 := createRandomAccountWithBalance(0)
+			scheduled := createScheduledTransfer(account1, account2, 30, model.ScheduleFrequencyDaily, time.Now().Add(-time.Minute))
+
+			result, err := store.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+				ID:  scheduled.ID,
+				Now: time.Now(),
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Transfer).To(BeNil())
+			Expect(result.Run.Outcome).To(Equal(ScheduledTransferRunSkipped))
+			Expect(result.Run.TransferID.Valid).To(BeFalse())
+			Expect(result.Run.FailureReason).NotTo(BeEmpty())
+			Expect(result.ScheduledTransfer.Occurrences).To(Equal(int32(1)))
+			Expect(result.ScheduledTransfer.Attempts).To(BeZero())
+
+			account, err := testQueries.GetAccount(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(account.Balance).To(Equal(int64(10)))
+		})
+
+		It("Test failed scheduled transfer is retried", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+			scheduled := createScheduledTransfer(account1, account2, 30, model.ScheduleFrequencyDaily, time.Now().Add(-time.Minute))
+
+			_, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+				AccountID: account1.ID,
+				Status:    model.AccountStatusFrozen,
+				Reason:    "changed by test",
+				ChangedBy: account1.Owner,
+			})
+			Expect(err).To(BeNil())
+
+			now := time.Now()
+			for attempt := int32(1); attempt <= MaxScheduledTransferAttempts; attempt++ {
+				result, err := store.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+					ID:  scheduled.ID,
+					Now: now,
+				})
+				Expect(err).To(BeNil())
+				Expect(result.Run.Outcome).To(Equal(ScheduledTransferRunFailed))
+				Expect(result.Run.Attempt).To(Equal(attempt))
+
+				if attempt < MaxScheduledTransferAttempts {
+					Expect(result.ScheduledTransfer.RetryAt.Valid).To(BeTrue())
+					Expect(result.ScheduledTransfer.Occurrences).To(BeZero())
+					now = result.ScheduledTransfer.RetryAt.Time
+				} else {
+					// The occurrence is given up after the last attempt
+					Expect(result.ScheduledTransfer.RetryAt.Valid).To(BeFalse())
+					Expect(result.ScheduledTransfer.Attempts).To(BeZero())
+					Expect(result.ScheduledTransfer.Occurrences).To(Equal(int32(1)))
+				}
+			}
+
+			runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+				ScheduledTransferID: scheduled.ID,
+				Limit:               10,
+				Offset:              0,
+			})
+			Expect(err).To(BeNil())
+			Expect(runs).To(HaveLen(MaxScheduledTransferAttempts))
+		})
+
+		It("Test paused scheduled transfer is not executed", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+			scheduled := createScheduledTransfer(account1, account2, 30, model.ScheduleFrequencyDaily, time.Now().Add(-time.Minute))
+
+			_, err := testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+				ID:     scheduled.ID,
+				Amount: scheduled.Amount,
+				Status: ScheduledTransferStatusPaused,
+				EndAt:  sql.NullTime{},
+			})
+			Expect(err).To(BeNil())
+
+			_, err = store.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+				ID:  scheduled.ID,
+				Now: time.Now(),
+			})
+			Expect(errors.Is(err, ErrScheduledTransferNotDue)).To(BeTrue())
+		})
+
+		It("Test completed scheduled transfer cannot be updated", func() {
+			account1 := createRandomAccountWithBalance(100)
+			account2 := createRandomAccountWithBalance(0)
+			scheduled := createScheduledTransfer(account1, account2, 30, model.ScheduleFrequencyOnce, time.Now().Add(time.Hour))
+
+			_, err := testQueries.UpdateScheduledTransferState(context.Background(), UpdateScheduledTransferStateParams{
+				ID:          scheduled.ID,
+				Status:      ScheduledTransferStatusCompleted,
+				NextRunAt:   scheduled.NextRunAt,
+				Occurrences: 1,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+				ID:     scheduled.ID,
+				Amount: scheduled.Amount,
+				Status: ScheduledTransferStatusActive,
+				EndAt:  sql.NullTime{},
+			})
+			Expect(errors.Is(err, sql.ErrNoRows)).To(BeTrue())
+
+			stored, err := testQueries.GetScheduledTransfer(context.Background(), scheduled.ID)
+			Expect(err).To(BeNil())
+			Expect(stored.Status).To(Equal(ScheduledTransferStatusCompleted))
+		})
+	})
+})
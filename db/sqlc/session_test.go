@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func createRandomSession() Session {
+	user := createRandomUser()
+	arg := CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     user.Username,
+		RefreshToken: util.GetRandomStringWithLength(32),
+		UserAgent:    "test-agent",
+		ClientIp:     "127.0.0.1",
+		IsBlocked:    false,
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	Expect(err).To(BeNil())
+	Expect(session.ID).To(Equal(arg.ID))
+	Expect(session.Username).To(Equal(arg.Username))
+	Expect(session.RefreshToken).To(Equal(arg.RefreshToken))
+	Expect(session.IsBlocked).To(BeFalse())
+	Expect(session.ExpiresAt).To(BeTemporally("~", arg.ExpiresAt, time.Second))
+
+	return session
+}
+
+var _ = Describe("SQL Session Operations", func() {
+	It("Test CreateSession", func() {
+		createRandomSession()
+	})
+
+	It("Test GetSession", func() {
+		session := createRandomSession()
+
+		got, err := testQueries.GetSession(context.Background(), session.ID)
+		Expect(err).To(BeNil())
+		Expect(got.ID).To(Equal(session.ID))
+		Expect(got.RefreshToken).To(Equal(session.RefreshToken))
+	})
+
+	It("Test BlockSession", func() {
+		session := createRandomSession()
+
+		blocked, err := testQueries.BlockSession(context.Background(), session.ID)
+		Expect(err).To(BeNil())
+		Expect(blocked.IsBlocked).To(BeTrue())
+	})
+})
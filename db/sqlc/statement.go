@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// AccountStatementTxParams contains the input parameters of the account statement transaction
+type AccountStatementTxParams struct {
+	AccountID int64     `json:"account_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	// Search is optional, it keeps the entries whose description or external reference contains it, ignoring case
+	Search string `json:"search,omitempty"`
+}
+
+// StatementEntry is an entry of an account statement with the balance right after it
+type StatementEntry struct {
+	Entry
+	Balance int64 `json:"balance"`
+}
+
+// AccountStatementTxResult is the result of the account statement transaction
+type AccountStatementTxResult struct {
+	Account        Account          `json:"account"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	OpeningBalance int64            `json:"opening_balance"`
+	Entries        []StatementEntry `json:"entries"`
+	ClosingBalance int64            `json:"closing_balance"`
+}
+
+// AccountStatementTx returns the entries of an account made in [From, To) with a running balance.
+// The opening balance is derived from the current balance minus every entry made since From,
+// so all reads are done in one repeatable read transaction to see a consistent snapshot.
+func (store SQLStore) AccountStatementTx(ctx context.Context, arg AccountStatementTxParams) (AccountStatementTxResult, error) {
+	var result AccountStatementTxResult
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTx(ctx, opts, func(q *Queries) error {
+		var err error
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		sinceFrom, err := q.SumEntriesSince(ctx, SumEntriesSinceParams{
+			AccountID: arg.AccountID,
+			Since:     arg.From,
+		})
+		if err != nil {
+			return err
+		}
+
+		entries, err := q.ListEntriesBetween(ctx, ListEntriesBetweenParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.From,
+			ToTime:    arg.To,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.From = arg.From
+		result.To = arg.To
+		result.OpeningBalance = result.Account.Balance - sinceFrom
+		result.Entries = make([]StatementEntry, 0, len(entries))
+
+		// Entries are filtered after the running balance is computed, so the balance of a matching entry stays right
+		balance := result.OpeningBalance
+		for _, entry := range entries {
+			balance += entry.Amount
+			if matchesEntrySearch(entry, arg.Search) {
+				result.Entries = append(result.Entries, StatementEntry{Entry: entry, Balance: balance})
+			}
+		}
+		result.ClosingBalance = balance
+		return nil
+	})
+
+	return result, err
+}
+
+// matchesEntrySearch reports whether the description or external reference of the entry contains search, ignoring case
+func matchesEntrySearch(entry Entry, search string) bool {
+	if search == "" {
+		return true
+	}
+	search = strings.ToLower(search)
+	return strings.Contains(strings.ToLower(entry.Description), search) ||
+		strings.Contains(strings.ToLower(entry.ExternalReference), search)
+}
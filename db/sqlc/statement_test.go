@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Account Statement", func() {
+	It("Test AccountStatementTx", func() {
+		store := NewStore(testDB)
+		account1 := createRandomAccount()
+		account2 := createRandomAccount()
+		from := time.Now().Add(-time.Minute)
+
+		amounts := []int64{10, 20, 30}
+		for _, amount := range amounts {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+			})
+			Expect(err).To(BeNil())
+		}
+
+		statement, err := store.AccountStatementTx(context.Background(), AccountStatementTxParams{
+			AccountID: account1.ID,
+			From:      from,
+			To:        time.Now().Add(time.Minute),
+		})
+		Expect(err).To(BeNil())
+		Expect(statement.Account.ID).To(Equal(account1.ID))
+		Expect(statement.OpeningBalance).To(Equal(account1.Balance))
+		Expect(statement.ClosingBalance).To(Equal(account1.Balance - 60))
+		Expect(statement.Entries).To(HaveLen(len(amounts)))
+
+		balance := account1.Balance
+		for i, entry := range statement.Entries {
+			balance -= amounts[i]
+			Expect(entry.AccountID).To(Equal(account1.ID))
+			Expect(entry.Amount).To(Equal(-amounts[i]))
+			Expect(entry.Balance).To(Equal(balance))
+		}
+	})
+
+	It("Test AccountStatementTx search", func() {
+		store := NewStore(testDB)
+		account1 := createRandomAccount()
+		account2 := createRandomAccount()
+		from := time.Now().Add(-time.Minute)
+
+		for _, description := range []string{"Groceries", "Rent for March", "Cinema"} {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        10,
+				Description:   description,
+			})
+			Expect(err).To(BeNil())
+		}
+
+		statement, err := store.AccountStatementTx(context.Background(), AccountStatementTxParams{
+			AccountID: account1.ID,
+			From:      from,
+			To:        time.Now().Add(time.Minute),
+			Search:    "RENT",
+		})
+		Expect(err).To(BeNil())
+		Expect(statement.Entries).To(HaveLen(1))
+		Expect(statement.Entries[0].Description).To(Equal("Rent for March"))
+		// The running balance still counts the entries that were filtered out
+		Expect(statement.Entries[0].Balance).To(Equal(account1.Balance - 20))
+		Expect(statement.ClosingBalance).To(Equal(account1.Balance - 30))
+	})
+
+	It("Test AccountStatementTx opening balance", func() {
+		store := NewStore(testDB)
+		account1 := createRandomAccount()
+		account2 := createRandomAccount()
+
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		Expect(err).To(BeNil())
+
+		// Entries made before the statement period only count towards the opening balance
+		statement, err := store.AccountStatementTx(context.Background(), AccountStatementTxParams{
+			AccountID: account1.ID,
+			From:      time.Now().Add(time.Minute),
+			To:        time.Now().Add(2 * time.Minute),
+		})
+		Expect(err).To(BeNil())
+		Expect(statement.OpeningBalance).To(Equal(account1.Balance - 10))
+		Expect(statement.ClosingBalance).To(Equal(account1.Balance - 10))
+		Expect(statement.Entries).To(BeEmpty())
+	})
+})
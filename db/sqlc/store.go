@@ -2,19 +2,46 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
+)
+
+var (
+	// ErrInsufficientFunds is returned when a transfer would take an account below its overdraft limit
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is replayed with different transfer parameters
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with different parameters")
+	// ErrAccountClosed is returned when a transfer would move money into or out of a closed account
+	ErrAccountClosed = errors.New("account is closed")
 )
 
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	FXTransferTx(ctx context.Context, arg FXTransferTxParams) (TransferTxResult, error)
+	BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error)
+	AccountStatementTx(ctx context.Context, arg AccountStatementTxParams) (AccountStatementTxResult, error)
+	ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error)
+	PlaceHold(ctx context.Context, arg PlaceHoldParams) (Hold, error)
+	CaptureHold(ctx context.Context, arg CaptureHoldParams) (CaptureHoldResult, error)
+	ReleaseHold(ctx context.Context, holdID int64) (Hold, error)
+	ExecuteScheduledTransferTx(ctx context.Context, arg ExecuteScheduledTransferTxParams) (ExecuteScheduledTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	VerifyLedger(ctx context.Context, arg VerifyLedgerParams) (LedgerReport, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error)
 }
 
 // SQLStore provides all functions to execute db queries and transactions
 type SQLStore struct {
 	*Queries
 	db *sql.DB
+	// wrapTx wraps the connection of each transaction when it is set, tests use it to inject faults
+	wrapTx func(DBTX) DBTX
 }
 
 // NewStore creates a new Store
@@ -28,12 +55,54 @@ func NewStore(db *sql.DB) Store {
 // ExecTx executes a function within a database transaction.
 // It rolls back the transaction if the function returns an error.
 // If the function returns nil, it commits the transaction.
+// A transaction that fails with a serialization failure or a deadlock is run again, see execTx.
 func (store SQLStore) ExecTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+	return store.execTx(ctx, nil, fn)
+}
+
+// ExecTxWithOptions executes a function within a database transaction started with the given options.
+// It behaves like ExecTx otherwise.
+func (store SQLStore) ExecTxWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	return store.execTx(ctx, opts, fn)
+}
+
+// execTx executes a function within a database transaction started with the given options.
+// Options without an isolation level take the one set on the context by WithTxIsolation.
+// The transaction is run again with a jittered backoff when it fails with a serialization failure or a deadlock,
+// up to MaxTxAttempts times, so fn must not have side effects outside the transaction.
+func (store SQLStore) execTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	opts = txOptions(ctx, opts)
+	for attempt := 1; ; attempt++ {
+		err := store.runTx(ctx, opts, fn)
+		reason, retryable := retryableTxError(err)
+		if !retryable {
+			return err
+		}
+		if attempt == MaxTxAttempts {
+			txRetries.Add(txRetriesExhausted, 1)
+			return err
+		}
+
+		txRetries.Add(reason, 1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(txRetryDelay(attempt)):
+		}
+	}
+}
+
+// runTx runs a function within a single database transaction started with the given options
+func (store SQLStore) runTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
-	q := New(tx)
+	var conn DBTX = tx
+	if store.wrapTx != nil {
+		conn = store.wrapTx(tx)
+	}
+	q := New(conn)
 	err = fn(q)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -49,6 +118,24 @@ type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// IdempotencyKey is optional. A replay with the same key returns the result of the first transfer.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// Description, ExternalReference and Metadata are optional details stored on the transfer,
+	// the description and reference are copied to both entries
+	Description       string          `json:"description,omitempty"`
+	ExternalReference string          `json:"external_reference,omitempty"`
+	Metadata          json.RawMessage `json:"metadata,omitempty"`
+}
+
+// requestHash identifies the transfer parameters an idempotency key is bound to.
+// The details are only hashed when they are set, so keys stored before they existed still match their replays.
+func (arg TransferTxParams) requestHash() string {
+	request := fmt.Sprintf("%d:%d:%d", arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	if arg.Description != "" || arg.ExternalReference != "" || len(arg.Metadata) > 0 {
+		request += fmt.Sprintf(":%q:%q:%s", arg.Description, arg.ExternalReference, arg.Metadata)
+	}
+	sum := sha256.Sum256([]byte(request))
+	return hex.EncodeToString(sum[:])
 }
 
 // TransferTxResult is the result of the transfer transaction
@@ -64,50 +151,202 @@ type TransferTxResult struct {
 // It creates a transfer record, add account entries, and update accounts' balance within a single database transaction.
 // It returns the newly created transfer and account entries.
 // If any of the operations fail, it rolls back the transaction and returns an error.
+// It returns ErrInsufficientFunds if the transfer would take the source account below its overdraft limit
+// or would spend funds that are on hold,
+// ErrAccountClosed if either account is closed, and ErrAccountFrozen if the status of an account blocks the transfer.
+// If an idempotency key is given, a replay of the key returns the original result instead of moving money again,
+// and a replay with different parameters returns ErrIdempotencyKeyReused.
 func (store SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	return store.FXTransferTx(ctx, FXTransferTxParams{
+		TransferTxParams: arg,
+		ToAmount:         arg.Amount,
+		ExchangeRate:     "1",
+	})
+}
+
+// FXTransferTxParams contains the input parameters of the cross-currency transfer transaction
+type FXTransferTxParams struct {
+	TransferTxParams
+	// ToAmount is the converted amount credited to the destination account
+	ToAmount int64 `json:"to_amount"`
+	// ExchangeRate is the rate that was applied to Amount to get ToAmount
+	ExchangeRate string `json:"exchange_rate"`
+}
+
+// FXTransferTx performs a money transfer between accounts of different currencies.
+// It debits Amount in the source currency and credits ToAmount in the destination currency,
+// and records both amounts with the applied rate on the transfer. It behaves like TransferTx otherwise.
+func (store SQLStore) FXTransferTx(ctx context.Context, arg FXTransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 	err := store.ExecTx(ctx, func(q *Queries) error {
-
 		var err error
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+		result, err = transferTx(ctx, q, arg)
+		return err
+	})
 
-		if err != nil {
-			return err
+	return result, err
+}
+
+// transferTx runs the checks and writes of FXTransferTx with queries that run inside a transaction
+func transferTx(ctx context.Context, q *Queries, arg FXTransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	if arg.IdempotencyKey != "" {
+		replayed, err := replayTransfer(ctx, q, arg.TransferTxParams, &result)
+		if err != nil || replayed {
+			return result, err
 		}
+	}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+	result, err := transfer(ctx, q, CreateTransferParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount,
+		ToAmount:          arg.ToAmount,
+		ExchangeRate:      arg.ExchangeRate,
+		Description:       arg.Description,
+		ExternalReference: arg.ExternalReference,
+		Metadata:          arg.Metadata,
+	})
+	if err != nil {
+		return result, err
+	}
 
-		if err != nil {
-			return err
-		}
+	// Funds on hold are reserved for their capture, so a transfer cannot spend them
+	err = checkAvailableBalance(ctx, q, result.FromAccount)
+	if err != nil {
+		return result, err
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	if arg.IdempotencyKey != "" {
+		return result, saveIdempotencyKey(ctx, q, arg.TransferTxParams, result)
+	}
+	return result, nil
+}
 
-		if err != nil {
-			return err
-		}
+// transfer creates the transfer record and entries and updates the balances of both accounts.
+// It must be called with queries that run inside a transaction.
+func transfer(ctx context.Context, q *Queries, arg CreateTransferParams) (TransferTxResult, error) {
+	result, err := moveFunds(ctx, q, arg)
+	if err != nil {
+		return result, err
+	}
 
-		// Update accounts' balance
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = depositAccount(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = depositAccount(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	// The balance returned by the update is read under the row lock, so concurrent transfers cannot both pass the check
+	if result.FromAccount.Balance < -result.FromAccount.OverdraftLimit {
+		return result, fmt.Errorf("%w: account %d", ErrInsufficientFunds, arg.FromAccountID)
+	}
 
-		}
-		return nil
+	return result, nil
+}
+
+// moveFunds writes a transfer like transfer and checks the status of both accounts, but not the overdraft limit
+// of the source account. It must be called with queries that run inside a transaction.
+func moveFunds(ctx context.Context, q *Queries, arg CreateTransferParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	if len(arg.Metadata) == 0 {
+		arg.Metadata = json.RawMessage(`{}`)
+	}
+
+	var err error
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+
+	if err != nil {
+		return result, err
+	}
+
+	// The entries point back to the transfer, so the ledger verification can check that they balance it
+	transferID := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:         arg.FromAccountID,
+		Amount:            -arg.Amount,
+		TransferID:        transferID,
+		Description:       arg.Description,
+		ExternalReference: arg.ExternalReference,
 	})
 
-	return result, err
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:         arg.ToAccountID,
+		Amount:            arg.ToAmount,
+		TransferID:        transferID,
+		Description:       arg.Description,
+		ExternalReference: arg.ExternalReference,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	// Update accounts' balance
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = depositAccount(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.ToAmount)
+	} else {
+		result.ToAccount, result.FromAccount, err = depositAccount(ctx, q, arg.ToAccountID, arg.ToAmount, arg.FromAccountID, -arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// Like the balance, the status is read under the row lock, so it cannot change while a transfer is in flight
+	if err := checkAccountDebit(result.FromAccount); err != nil {
+		return result, err
+	}
+	if err := checkAccountCredit(result.ToAccount); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// replayTransfer looks up the idempotency key of the transfer and loads the stored result into result.
+// Keys are scoped to the source account, so only the owner of the account can replay its transfers.
+// It holds a transaction-scoped lock on the key, so concurrent requests with the same key are serialized.
+func replayTransfer(ctx context.Context, q *Queries, arg TransferTxParams, result *TransferTxResult) (bool, error) {
+	err := q.LockIdempotencyKey(ctx, LockIdempotencyKeyParams{
+		FromAccountID: arg.FromAccountID,
+		Key:           arg.IdempotencyKey,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	key, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		FromAccountID: arg.FromAccountID,
+		Key:           arg.IdempotencyKey,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if key.RequestHash != arg.requestHash() {
+		return false, ErrIdempotencyKeyReused
+	}
+
+	return true, json.Unmarshal(key.Result, result)
+}
+
+// saveIdempotencyKey stores the result of the transfer under its idempotency key
+func saveIdempotencyKey(ctx context.Context, q *Queries, arg TransferTxParams, result TransferTxResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Key:           arg.IdempotencyKey,
+		RequestHash:   arg.requestHash(),
+		TransferID:    result.Transfer.ID,
+		Result:        data,
+		FromAccountID: arg.FromAccountID,
+	})
+	return err
 }
 
 // depositAccount adds amount to account balance and returns the updated account
@@ -2,21 +2,28 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
+// createRandomAccount creates an account with enough balance to cover the transfers made by the tests below
 func createRandomAccount() Account {
+	return createRandomAccountWithBalance(util.GetRandomIntWithRange(100, 1000))
+}
+
+func createRandomAccountWithBalance(balance int64) Account {
 	testOwnerName := createRandomUser()
-	testBalance := util.GetRandomMoneyAmount()
 	testCurrency := util.GetRandomCurrency()
 	arg := CreateAccountParams{
 		Owner:    testOwnerName.Username,
-		Balance:  testBalance,
+		Balance:  balance,
 		Currency: testCurrency,
+		Type:     string(model.AccountTypeChecking),
 	}
 
 	account, _ := testQueries.CreateAccount(context.Background(), arg)
@@ -174,5 +181,301 @@ var _ = Describe("Operation", func() {
 			Expect(updateAccount1.Balance).To(Equal(account1.Balance))
 			Expect(updateAccount2.Balance).To(Equal(account2.Balance))
 		})
+
+		It("Test insufficient funds", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(50)
+			account2 := createRandomAccount()
+
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        51,
+			})
+			Expect(errors.Is(err, ErrInsufficientFunds)).To(BeTrue())
+
+			// Nothing of the rejected transfer is committed
+			updateAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(updateAccount1.Balance).To(Equal(account1.Balance))
+
+			updateAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+			Expect(err).To(BeNil())
+			Expect(updateAccount2.Balance).To(Equal(account2.Balance))
+
+			entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+				AccountID: account1.ID,
+				Limit:     5,
+				Offset:    0,
+			})
+			Expect(err).To(BeNil())
+			Expect(entries).To(BeEmpty())
+		})
+
+		It("Test transfer to a closed account", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccount()
+			account2 := createRandomAccountWithBalance(0)
+
+			_, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+				AccountID: account2.ID,
+				Status:    model.AccountStatusClosed,
+				Reason:    "closed by test",
+				ChangedBy: account2.Owner,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        10,
+			})
+			Expect(errors.Is(err, ErrAccountClosed)).To(BeTrue())
+
+			updateAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(updateAccount1.Balance).To(Equal(account1.Balance))
+		})
+
+		It("Test overdraft limit", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(50)
+			account2 := createRandomAccount()
+
+			account1, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+				ID:             account1.ID,
+				OverdraftLimit: 100,
+			})
+			Expect(err).To(BeNil())
+			Expect(account1.OverdraftLimit).To(Equal(int64(100)))
+
+			result, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        150,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.FromAccount.Balance).To(Equal(int64(-100)))
+
+			_, err = store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        1,
+			})
+			Expect(errors.Is(err, ErrInsufficientFunds)).To(BeTrue())
+		})
+
+		It("Test cross-currency transfer", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+
+			result, err := store.FXTransferTx(context.Background(), FXTransferTxParams{
+				TransferTxParams: TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        50,
+				},
+				ToAmount:     46,
+				ExchangeRate: "0.92",
+			})
+			Expect(err).To(BeNil())
+
+			transfer, err := store.GetTransfer(context.Background(), result.Transfer.ID)
+			Expect(err).To(BeNil())
+			Expect(transfer.Amount).To(Equal(int64(50)))
+			Expect(transfer.ToAmount).To(Equal(int64(46)))
+			Expect(transfer.ExchangeRate).To(Equal("0.9200000000"))
+
+			Expect(result.FromEntry.Amount).To(Equal(int64(-50)))
+			Expect(result.ToEntry.Amount).To(Equal(int64(46)))
+			Expect(result.FromAccount.Balance).To(Equal(account1.Balance - 50))
+			Expect(result.ToAccount.Balance).To(Equal(account2.Balance + 46))
+		})
+
+		It("Test concurrent idempotent replays", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+
+			n := 5
+			amount := int64(10)
+			arg := TransferTxParams{
+				FromAccountID:  account1.ID,
+				ToAccountID:    account2.ID,
+				Amount:         amount,
+				IdempotencyKey: util.GetRandomStringWithLength(32),
+			}
+
+			errs := make(chan error)
+			results := make(chan TransferTxResult)
+
+			for i := 0; i < n; i++ {
+				go func() {
+					result, err := store.TransferTx(context.Background(), arg)
+
+					errs <- err
+					results <- result
+				}()
+			}
+
+			var first TransferTxResult
+			for i := 0; i < n; i++ {
+				err := <-errs
+				result := <-results
+				Expect(err).To(BeNil())
+				Expect(result.Transfer.ID).NotTo(BeZero())
+
+				if i == 0 {
+					first = result
+					continue
+				}
+				Expect(result.Transfer.ID).To(Equal(first.Transfer.ID))
+				Expect(result.FromEntry.ID).To(Equal(first.FromEntry.ID))
+				Expect(result.ToEntry.ID).To(Equal(first.ToEntry.ID))
+				Expect(result.FromAccount.Balance).To(Equal(first.FromAccount.Balance))
+				Expect(result.ToAccount.Balance).To(Equal(first.ToAccount.Balance))
+			}
+
+			// The money only moves once
+			updateAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(updateAccount1.Balance).To(Equal(account1.Balance - amount))
+
+			updateAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+			Expect(err).To(BeNil())
+			Expect(updateAccount2.Balance).To(Equal(account2.Balance + amount))
+
+			// A replay with different parameters is rejected
+			arg.Amount = amount + 1
+			_, err = store.TransferTx(context.Background(), arg)
+			Expect(errors.Is(err, ErrIdempotencyKeyReused)).To(BeTrue())
+		})
+
+		It("Test idempotency keys are scoped to the source account", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+			account3 := createRandomAccount()
+			key := util.GetRandomStringWithLength(32)
+
+			first, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID:  account1.ID,
+				ToAccountID:    account3.ID,
+				Amount:         10,
+				IdempotencyKey: key,
+			})
+			Expect(err).To(BeNil())
+
+			// The same key from another account is a new transfer, not a replay of the first one
+			second, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID:  account2.ID,
+				ToAccountID:    account3.ID,
+				Amount:         20,
+				IdempotencyKey: key,
+			})
+			Expect(err).To(BeNil())
+			Expect(second.Transfer.ID).NotTo(Equal(first.Transfer.ID))
+			Expect(second.Transfer.FromAccountID).To(Equal(account2.ID))
+
+			updateAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+			Expect(err).To(BeNil())
+			Expect(updateAccount2.Balance).To(Equal(account2.Balance - 20))
+		})
+
+		It("Test concurrent transfers cannot overdraw", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccountWithBalance(50)
+			account2 := createRandomAccount()
+
+			n := 10
+			amount := int64(10)
+			errs := make(chan error)
+
+			for i := 0; i < n; i++ {
+				go func() {
+					_, err := store.TransferTx(context.Background(), TransferTxParams{
+						FromAccountID: account1.ID,
+						ToAccountID:   account2.ID,
+						Amount:        amount,
+					})
+
+					errs <- err
+				}()
+			}
+
+			succeeded := 0
+			for i := 0; i < n; i++ {
+				err := <-errs
+				if err == nil {
+					succeeded++
+					continue
+				}
+				Expect(errors.Is(err, ErrInsufficientFunds)).To(BeTrue())
+			}
+			Expect(succeeded).To(Equal(5))
+
+			updateAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+			Expect(err).To(BeNil())
+			Expect(updateAccount1.Balance).To(Equal(int64(0)))
+		})
+
+		It("Test transfer rolls back at every failure point", func() {
+			for _, withKey := range []bool{false, true} {
+				arg := func(account1, account2 Account) TransferTxParams {
+					arg := TransferTxParams{
+						FromAccountID: account1.ID,
+						ToAccountID:   account2.ID,
+						Amount:        10,
+					}
+					if withKey {
+						arg.IdempotencyKey = util.GetRandomStringWithLength(32)
+					}
+					return arg
+				}
+
+				// Count the queries of a transfer that goes through
+				counter := &faultInjector{}
+				_, err := counter.store(testDB).TransferTx(context.Background(), arg(createRandomAccount(), createRandomAccount()))
+				Expect(err).To(BeNil())
+				Expect(counter.txs).To(HaveLen(1))
+				queries := counter.txs[0].queries
+				Expect(queries).To(BeNumerically(">=", 5))
+
+				for failAt := 1; failAt <= queries; failAt++ {
+					account1 := createRandomAccount()
+					account2 := createRandomAccount()
+
+					injector := &faultInjector{failAt: failAt}
+					_, err := injector.store(testDB).TransferTx(context.Background(), arg(account1, account2))
+					Expect(err).NotTo(BeNil(), "query %d of %d did not fail", failAt, queries)
+					Expect(err.Error()).To(ContainSubstring(errInjectedFault))
+
+					// Nothing the transfer wrote before the failure is left behind
+					for _, account := range []Account{account1, account2} {
+						updated, err := testQueries.GetAccount(context.Background(), account.ID)
+						Expect(err).To(BeNil())
+						Expect(updated.Balance).To(Equal(account.Balance), "query %d of %d", failAt, queries)
+
+						entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+							AccountID: account.ID,
+							Limit:     5,
+							Offset:    0,
+						})
+						Expect(err).To(BeNil())
+						Expect(entries).To(BeEmpty(), "query %d of %d", failAt, queries)
+					}
+
+					transfers, err := testQueries.ListTransfers(context.Background(), ListTransfersParams{
+						FromAccountID: account1.ID,
+						ToAccountID:   account2.ID,
+						Limit:         5,
+						Offset:        0,
+					})
+					Expect(err).To(BeNil())
+					Expect(transfers).To(BeEmpty(), "query %d of %d", failAt, queries)
+				}
+			}
+		})
 	})
 })
@@ -5,26 +5,50 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    reversal_of,
+    description,
+    external_reference,
+    metadata
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, description, external_reference, metadata
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID     int64           `json:"from_account_id"`
+	ToAccountID       int64           `json:"to_account_id"`
+	Amount            int64           `json:"amount"`
+	ToAmount          int64           `json:"to_amount"`
+	ExchangeRate      string          `json:"exchange_rate"`
+	ReversalOf        sql.NullInt64   `json:"reversal_of"`
+	Description       string          `json:"description"`
+	ExternalReference string          `json:"external_reference"`
+	Metadata          json.RawMessage `json:"metadata"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.ReversalOf,
+		arg.Description,
+		arg.ExternalReference,
+		arg.Metadata,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -32,12 +56,18 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, description, external_reference, metadata FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -50,31 +80,71 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Metadata,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, description, external_reference, metadata FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, description, external_reference, metadata FROM transfers
 WHERE
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = $1 OR
+    to_account_id = $2)
+  AND ($3::varchar IS NULL OR external_reference = $3::varchar)
+  AND ($4::varchar IS NULL OR strpos(lower(description), lower($4::varchar)) > 0)
+  AND ($5::text IS NULL OR metadata @> CAST($5::text AS jsonb))
 ORDER BY id
-LIMIT $3
-    OFFSET $4
+LIMIT $6
+    OFFSET $7
 `
 
 type ListTransfersParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Limit         int32 `json:"limit"`
-	Offset        int32 `json:"offset"`
+	FromAccountID     int64          `json:"from_account_id"`
+	ToAccountID       int64          `json:"to_account_id"`
+	ExternalReference sql.NullString `json:"external_reference"`
+	Search            sql.NullString `json:"search"`
+	Metadata          sql.NullString `json:"metadata"`
+	Limit             int32          `json:"limit"`
+	Offset            int32          `json:"offset"`
 }
 
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
 	rows, err := q.db.QueryContext(ctx, listTransfers,
 		arg.FromAccountID,
 		arg.ToAccountID,
+		arg.ExternalReference,
+		arg.Search,
+		arg.Metadata,
 		arg.Limit,
 		arg.Offset,
 	)
@@ -91,6 +161,12 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ReversalOf,
+			&i.Description,
+			&i.ExternalReference,
+			&i.Metadata,
 		); err != nil {
 			return nil, err
 		}
@@ -104,3 +180,114 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const searchTransfers = `-- name: SearchTransfers :many
+SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.to_amount, t.exchange_rate, t.reversal_of, t.description, t.external_reference, t.metadata, fa.currency AS from_currency, ta.currency AS to_currency FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE (($1::bool
+        AND fa.owner = $2
+        AND ($3::bigint IS NULL OR fa.id = $3::bigint)
+        AND ($4::varchar IS NULL OR fa.currency = $4::varchar)
+        AND ($5::bigint IS NULL OR t.amount >= $5::bigint)
+        AND ($6::bigint IS NULL OR t.amount <= $6::bigint))
+    OR ($7::bool
+        AND ta.owner = $2
+        AND ($3::bigint IS NULL OR ta.id = $3::bigint)
+        AND ($4::varchar IS NULL OR ta.currency = $4::varchar)
+        AND ($5::bigint IS NULL OR t.to_amount >= $5::bigint)
+        AND ($6::bigint IS NULL OR t.to_amount <= $6::bigint)))
+  AND ($8::timestamptz IS NULL OR t.created_at >= $8::timestamptz)
+  AND ($9::timestamptz IS NULL OR t.created_at < $9::timestamptz)
+  AND ($10::varchar IS NULL OR t.external_reference = $10::varchar)
+  AND ($11::varchar IS NULL OR strpos(lower(t.description), lower($11::varchar)) > 0)
+  AND ($12::bigint IS NULL OR t.id < $12::bigint)
+ORDER BY t.id DESC
+LIMIT $13
+`
+
+type SearchTransfersParams struct {
+	Outgoing          bool           `json:"outgoing"`
+	Owner             string         `json:"owner"`
+	AccountID         sql.NullInt64  `json:"account_id"`
+	Currency          sql.NullString `json:"currency"`
+	MinAmount         sql.NullInt64  `json:"min_amount"`
+	MaxAmount         sql.NullInt64  `json:"max_amount"`
+	Incoming          bool           `json:"incoming"`
+	FromTime          sql.NullTime   `json:"from_time"`
+	ToTime            sql.NullTime   `json:"to_time"`
+	ExternalReference sql.NullString `json:"external_reference"`
+	Search            sql.NullString `json:"search"`
+	BeforeID          sql.NullInt64  `json:"before_id"`
+	Limit             int32          `json:"limit"`
+}
+
+type SearchTransfersRow struct {
+	Transfer     Transfer `json:"transfer"`
+	FromCurrency string   `json:"from_currency"`
+	ToCurrency   string   `json:"to_currency"`
+}
+
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfers,
+		arg.Outgoing,
+		arg.Owner,
+		arg.AccountID,
+		arg.Currency,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.Incoming,
+		arg.FromTime,
+		arg.ToTime,
+		arg.ExternalReference,
+		arg.Search,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTransfersRow{}
+	for rows.Next() {
+		var i SearchTransfersRow
+		if err := rows.Scan(
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.ToAmount,
+			&i.Transfer.ExchangeRate,
+			&i.Transfer.ReversalOf,
+			&i.Transfer.Description,
+			&i.Transfer.ExternalReference,
+			&i.Transfer.Metadata,
+			&i.FromCurrency,
+			&i.ToCurrency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumTransferReversals = `-- name: SumTransferReversals :one
+SELECT COALESCE(SUM(to_amount), 0)::bigint AS total
+FROM transfers
+WHERE reversal_of = $1
+`
+
+func (q *Queries) SumTransferReversals(ctx context.Context, reversalOf sql.NullInt64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumTransferReversals, reversalOf)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
@@ -2,7 +2,10 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -18,6 +21,7 @@ var _ = Describe("SQL Transfer Operations", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, _ := testQueries.CreateAccount(context.Background(), arg)
@@ -26,9 +30,13 @@ var _ = Describe("SQL Transfer Operations", func() {
 				FromAccountID: account.ID,
 				ToAccountID:   account.ID,
 				Amount:        100,
+				ToAmount:      100,
+				ExchangeRate:  "1",
+				Metadata:      json.RawMessage(`{}`),
 			})
 			Expect(err).To(BeNil())
 			Expect(transfer.Amount).To(Equal(int64(100)))
+			Expect(transfer.ToAmount).To(Equal(int64(100)))
 		})
 
 		It("Test GetTransfer", func() {
@@ -39,6 +47,7 @@ var _ = Describe("SQL Transfer Operations", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, _ := testQueries.CreateAccount(context.Background(), arg)
@@ -49,6 +58,9 @@ var _ = Describe("SQL Transfer Operations", func() {
 				FromAccountID: account.ID,
 				ToAccountID:   account.ID,
 				Amount:        transferAmount,
+				ToAmount:      transferAmount,
+				ExchangeRate:  "1",
+				Metadata:      json.RawMessage(`{}`),
 			})
 			Expect(err).To(BeNil())
 			Expect(transfer.Amount).To(Equal(transferAmount))
@@ -62,6 +74,7 @@ var _ = Describe("SQL Transfer Operations", func() {
 				Owner:    testOwnerName.Username,
 				Balance:  testBalance,
 				Currency: testCurrency,
+				Type:     string(model.AccountTypeChecking),
 			}
 
 			account, _ := testQueries.CreateAccount(context.Background(), arg)
@@ -71,6 +84,9 @@ var _ = Describe("SQL Transfer Operations", func() {
 				FromAccountID: account.ID,
 				ToAccountID:   account.ID,
 				Amount:        transferAmount,
+				ToAmount:      transferAmount,
+				ExchangeRate:  "1",
+				Metadata:      json.RawMessage(`{}`),
 			})
 			Expect(err).To(BeNil())
 
@@ -78,6 +94,9 @@ var _ = Describe("SQL Transfer Operations", func() {
 				FromAccountID: account.ID,
 				ToAccountID:   account.ID,
 				Amount:        transferAmount,
+				ToAmount:      transferAmount,
+				ExchangeRate:  "1",
+				Metadata:      json.RawMessage(`{}`),
 			})
 			Expect(err).To(BeNil())
 
@@ -92,6 +111,123 @@ var _ = Describe("SQL Transfer Operations", func() {
 
 		})
 
+		It("Test transfer details are stored and searchable", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+
+			result, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID:     account1.ID,
+				ToAccountID:       account2.ID,
+				Amount:            10,
+				Description:       "Rent for March",
+				ExternalReference: "INV-1001",
+				Metadata:          json.RawMessage(`{"category": "housing", "month": 3}`),
+			})
+			Expect(err).To(BeNil())
+			Expect(result.Transfer.Description).To(Equal("Rent for March"))
+			Expect(result.Transfer.ExternalReference).To(Equal("INV-1001"))
+			Expect(result.Transfer.Metadata).To(MatchJSON(`{"category": "housing", "month": 3}`))
+			Expect(result.FromEntry.Description).To(Equal("Rent for March"))
+			Expect(result.ToEntry.ExternalReference).To(Equal("INV-1001"))
+
+			// Transfers without details get empty metadata
+			plain, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        10,
+			})
+			Expect(err).To(BeNil())
+			Expect(plain.Transfer.Metadata).To(MatchJSON(`{}`))
+
+			list := func(arg ListTransfersParams) []int64 {
+				arg.FromAccountID = account1.ID
+				arg.ToAccountID = account1.ID
+				arg.Limit = 5
+				transfers, err := testQueries.ListTransfers(context.Background(), arg)
+				Expect(err).To(BeNil())
+				ids := []int64{}
+				for _, transfer := range transfers {
+					ids = append(ids, transfer.ID)
+				}
+				return ids
+			}
+
+			Expect(list(ListTransfersParams{})).To(Equal([]int64{result.Transfer.ID, plain.Transfer.ID}))
+			Expect(list(ListTransfersParams{
+				Search: sql.NullString{String: "rent", Valid: true},
+			})).To(Equal([]int64{result.Transfer.ID}))
+			Expect(list(ListTransfersParams{
+				ExternalReference: sql.NullString{String: "INV-1001", Valid: true},
+			})).To(Equal([]int64{result.Transfer.ID}))
+			Expect(list(ListTransfersParams{
+				Metadata: sql.NullString{String: `{"category": "housing"}`, Valid: true},
+			})).To(Equal([]int64{result.Transfer.ID}))
+			Expect(list(ListTransfersParams{
+				Search: sql.NullString{String: "salary", Valid: true},
+			})).To(BeEmpty())
+		})
+
+		It("Test SearchTransfers", func() {
+			store := NewStore(testDB)
+			account1 := createRandomAccount()
+			account2 := createRandomAccount()
+
+			transfer := func(from, to Account, amount int64) int64 {
+				result, err := store.TransferTx(context.Background(), TransferTxParams{
+					FromAccountID: from.ID,
+					ToAccountID:   to.ID,
+					Amount:        amount,
+				})
+				Expect(err).To(BeNil())
+				return result.Transfer.ID
+			}
+			out1 := transfer(account1, account2, 10)
+			in1 := transfer(account2, account1, 20)
+			out2 := transfer(account1, account2, 30)
+
+			accountCurrency := func(accountID int64) string {
+				if accountID == account1.ID {
+					return account1.Currency
+				}
+				return account2.Currency
+			}
+
+			search := func(arg SearchTransfersParams) []int64 {
+				arg.Owner = account1.Owner
+				arg.Limit = 5
+				transfers, err := testQueries.SearchTransfers(context.Background(), arg)
+				Expect(err).To(BeNil())
+				ids := []int64{}
+				for _, row := range transfers {
+					Expect(row.FromCurrency).To(Equal(accountCurrency(row.Transfer.FromAccountID)))
+					Expect(row.ToCurrency).To(Equal(accountCurrency(row.Transfer.ToAccountID)))
+					ids = append(ids, row.Transfer.ID)
+				}
+				return ids
+			}
+
+			Expect(search(SearchTransfersParams{Outgoing: true, Incoming: true})).To(Equal([]int64{out2, in1, out1}))
+			Expect(search(SearchTransfersParams{Outgoing: true})).To(Equal([]int64{out2, out1}))
+			Expect(search(SearchTransfersParams{Incoming: true})).To(Equal([]int64{in1}))
+			Expect(search(SearchTransfersParams{
+				Outgoing:  true,
+				Incoming:  true,
+				MinAmount: sql.NullInt64{Int64: 15, Valid: true},
+				MaxAmount: sql.NullInt64{Int64: 25, Valid: true},
+			})).To(Equal([]int64{in1}))
+			Expect(search(SearchTransfersParams{
+				Outgoing: true,
+				Incoming: true,
+				BeforeID: sql.NullInt64{Int64: in1, Valid: true},
+			})).To(Equal([]int64{out1}))
+			Expect(search(SearchTransfersParams{
+				Outgoing: true,
+				Incoming: true,
+				Currency: sql.NullString{String: "XXX", Valid: true},
+			})).To(BeEmpty())
+		})
+
 	})
 
 })
@@ -48,6 +48,24 @@ var _ = Describe("Operation", func() {
 			Expect(err).To(BeNil())
 			Expect(user.Username).To(Equal(arg.Username))
 		})
+
+		It("Test UpdateUserRole", func() {
+			user := createRandomUser()
+
+			updated, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+				Username: user.Username,
+				Role:     "teller",
+			})
+			Expect(err).To(BeNil())
+			Expect(updated.Username).To(Equal(user.Username))
+			Expect(updated.Role).To(Equal("teller"))
+
+			_, err = testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+				Username: user.Username,
+				Role:     "superuser",
+			})
+			Expect(err).NotTo(BeNil())
+		})
 	})
 
 })
@@ -7,11 +7,24 @@ import (
 
 // Config defines the configuration structure for the application
 type Config struct {
-	DBDriver          string        `mapstructure:"DB_DRIVER"`
-	DBSource          string        `mapstructure:"DB_SOURCE"`
-	ServerAddress     string        `mapstructure:"SERVER_ADDRESS"`
-	TokenSymmetricKey string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessToken       time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	DBDriver                  string        `mapstructure:"DB_DRIVER"`
+	DBSource                  string        `mapstructure:"DB_SOURCE"`
+	ServerAddress             string        `mapstructure:"SERVER_ADDRESS"`
+	TokenType                 string        `mapstructure:"TOKEN_TYPE"`
+	TokenAlgorithm            string        `mapstructure:"TOKEN_ALGORITHM"`
+	TokenSymmetricKey         string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenPrivateKeyFile       string        `mapstructure:"TOKEN_PRIVATE_KEY_FILE"`
+	AccessToken               time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration      time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	TokenRevocationStore      string        `mapstructure:"TOKEN_REVOCATION_STORE"`
+	FXRatesFile               string        `mapstructure:"FX_RATES_FILE"`
+	CurrencyRegistry          string        `mapstructure:"CURRENCY_REGISTRY"`
+	CurrenciesFile            string        `mapstructure:"CURRENCIES_FILE"`
+	CurrencyReloadInterval    time.Duration `mapstructure:"CURRENCY_RELOAD_INTERVAL"`
+	HoldReleaseInterval       time.Duration `mapstructure:"HOLD_RELEASE_INTERVAL"`
+	ScheduledTransferInterval time.Duration `mapstructure:"SCHEDULED_TRANSFER_INTERVAL"`
+	InterestRatesFile         string        `mapstructure:"INTEREST_RATES_FILE"`
+	InterestAccrualInterval   time.Duration `mapstructure:"INTEREST_ACCRUAL_INTERVAL"`
 }
 
 // LoadConfig loads the configuration from file and environment variables
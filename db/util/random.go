@@ -38,14 +38,11 @@ func GetRandomOwnerName() string {
 	return GetRandomStringWithLength(length)
 }
 
-// GetRandomCurrency generate a random currency code
+// GetRandomCurrency generate a random currency code out of the currencies seeded in the registry
 func GetRandomCurrency() string {
 	// List of currency code for testing
-	currencyList := make([]string, 0, len(model.CurrencyMap))
-	for _, value := range model.CurrencyMap {
-		currencyList = append(currencyList, value)
-	}
-	return currencyList[rand.Intn(len(currencyList))]
+	currencyList := []model.CurrencyType{model.USD, model.EUR, model.CAD}
+	return string(currencyList[rand.Intn(len(currencyList))])
 }
 
 // GetRandomEmail generate a random email
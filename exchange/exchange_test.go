@@ -0,0 +1,166 @@
+package exchange
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestExchange(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Unit Test for exchange rates")
+}
+
+var _ = Describe("Rate", func() {
+	It("Test parse rate", func() {
+		rate, err := ParseRate("0.92")
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("0.92"))
+
+		_, err = ParseRate("abc")
+		Expect(err).To(MatchError(ErrInvalidRate))
+
+		_, err = ParseRate("-1")
+		Expect(err).To(MatchError(ErrInvalidRate))
+
+		// Positive rates that round to zero, or whose inverse does, cannot be inverted or converted with
+		_, err = ParseRate("0.00000000001")
+		Expect(err).To(MatchError(ErrInvalidRate))
+		_, err = ParseRate("100000000000")
+		Expect(err).To(MatchError(ErrInvalidRate))
+
+		rate, err = ParseRate("0.00000000006")
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("0.0000000001"))
+	})
+
+	It("Test convert rounds half away from zero", func() {
+		rate, err := ParseRate("0.925")
+		Expect(err).To(BeNil())
+
+		amount, err := rate.Convert(10, 2, 2)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(9)))
+
+		amount, err = rate.Convert(100, 2, 2)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(93)))
+	})
+
+	It("Test convert scales by the currency exponents", func() {
+		// 12.34 USD at 149.5 is 1844.83 JPY, which has no minor unit
+		rate, err := ParseRate("149.5")
+		Expect(err).To(BeNil())
+		amount, err := rate.Convert(1234, 2, 0)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(1845)))
+
+		// 1845 JPY at 0.0067 is 12.3615 USD
+		rate, err = ParseRate("0.0067")
+		Expect(err).To(BeNil())
+		amount, err = rate.Convert(1845, 0, 2)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(1236)))
+
+		// 10.00 USD at 0.308 is 3.080 KWD, which has three decimal places
+		rate, err = ParseRate("0.308")
+		Expect(err).To(BeNil())
+		amount, err = rate.Convert(1000, 2, 3)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(3080)))
+	})
+
+	It("Test convert overflow", func() {
+		rate, err := ParseRate("2")
+		Expect(err).To(BeNil())
+
+		_, err = rate.Convert(1<<62, 2, 2)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("Test inverse is rounded to the rate scale", func() {
+		rate, err := ParseRate("3")
+		Expect(err).To(BeNil())
+		Expect(rate.Inverse().String()).To(Equal("0.3333333333"))
+	})
+})
+
+var _ = Describe("FXRateProvider", func() {
+	It("Test static provider", func() {
+		provider, err := NewStaticRateProvider(DefaultQuotes)
+		Expect(err).To(BeNil())
+
+		rate, err := provider.Rate(model.USD, model.EUR)
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("0.92"))
+
+		rate, err = provider.Rate(model.EUR, model.USD)
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("1.0869565217"))
+
+		rate, err = provider.Rate(model.CAD, model.CAD)
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("1"))
+	})
+
+	It("Test static provider missing pair", func() {
+		provider, err := NewStaticRateProvider([]Quote{{From: model.USD, To: model.EUR, Rate: "0.92"}})
+		Expect(err).To(BeNil())
+
+		_, err = provider.Rate(model.USD, model.CAD)
+		Expect(err).To(MatchError(ErrRateNotFound))
+	})
+
+	It("Test static provider rejects malformed currency", func() {
+		_, err := NewStaticRateProvider([]Quote{{From: model.USD, To: "xyz", Rate: "2"}})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("Test file provider", func() {
+		dir, err := os.MkdirTemp("", "exchange")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "rates.json")
+		err = os.WriteFile(path, []byte(`[{"from": "USD", "to": "CAD", "rate": "1.35"}]`), 0o600)
+		Expect(err).To(BeNil())
+
+		provider, err := NewFileRateProvider(path)
+		Expect(err).To(BeNil())
+
+		rate, err := provider.Rate(model.USD, model.CAD)
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("1.35"))
+
+		// An invalid file keeps the previous table
+		err = os.WriteFile(path, []byte(`not json`), 0o600)
+		Expect(err).To(BeNil())
+		Expect(provider.Reload()).NotTo(BeNil())
+
+		rate, err = provider.Rate(model.USD, model.CAD)
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("1.35"))
+
+		// A rate that rounds to zero is rejected instead of panicking when it is inverted
+		err = os.WriteFile(path, []byte(`[{"from": "USD", "to": "CAD", "rate": "0.00000000001"}]`), 0o600)
+		Expect(err).To(BeNil())
+		Expect(provider.Reload()).To(MatchError(ErrInvalidRate))
+
+		rate, err = provider.Rate(model.USD, model.CAD)
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("1.35"))
+	})
+
+	It("Test file provider with missing file", func() {
+		dir, err := os.MkdirTemp("", "exchange")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		_, err = NewFileRateProvider(filepath.Join(dir, "missing.json"))
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,128 @@
+package exchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+)
+
+// ErrRateNotFound is returned when a provider has no rate for a currency pair
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// FXRateProvider provides the exchange rate between two currencies
+type FXRateProvider interface {
+	// Rate returns the rate that converts an amount in from into an amount in to
+	Rate(from, to model.CurrencyType) (Rate, error)
+}
+
+// Quote is a single entry of a rate table
+type Quote struct {
+	From model.CurrencyType `json:"from"`
+	To   model.CurrencyType `json:"to"`
+	Rate string             `json:"rate"`
+}
+
+// DefaultQuotes is the rate table used when no rate file is configured
+var DefaultQuotes = []Quote{
+	{From: model.USD, To: model.EUR, Rate: "0.92"},
+	{From: model.USD, To: model.CAD, Rate: "1.36"},
+	{From: model.EUR, To: model.CAD, Rate: "1.48"},
+}
+
+type currencyPair struct {
+	from model.CurrencyType
+	to   model.CurrencyType
+}
+
+// StaticRateProvider serves rates from a fixed table
+type StaticRateProvider struct {
+	rates map[currencyPair]Rate
+}
+
+// NewStaticRateProvider creates a StaticRateProvider from the given quotes.
+// The inverse of a quote is derived unless the table quotes that direction too.
+func NewStaticRateProvider(quotes []Quote) (*StaticRateProvider, error) {
+	rates := make(map[currencyPair]Rate, 2*len(quotes))
+	for _, quote := range quotes {
+		if !quote.From.IsValid() || !quote.To.IsValid() {
+			return nil, fmt.Errorf("invalid currency pair %s/%s", quote.From, quote.To)
+		}
+		rate, err := ParseRate(quote.Rate)
+		if err != nil {
+			return nil, err
+		}
+		rates[currencyPair{quote.From, quote.To}] = rate
+	}
+
+	for pair, rate := range rates {
+		inverse := currencyPair{pair.to, pair.from}
+		if _, ok := rates[inverse]; !ok {
+			rates[inverse] = rate.Inverse()
+		}
+	}
+
+	return &StaticRateProvider{rates: rates}, nil
+}
+
+// Rate returns the rate that converts an amount in from into an amount in to
+func (provider *StaticRateProvider) Rate(from, to model.CurrencyType) (Rate, error) {
+	if from == to {
+		return ParseRate("1")
+	}
+	rate, ok := provider.rates[currencyPair{from, to}]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+	}
+	return rate, nil
+}
+
+// FileRateProvider serves rates from a JSON file holding a list of quotes
+type FileRateProvider struct {
+	path string
+
+	mu     sync.RWMutex
+	static *StaticRateProvider
+}
+
+// NewFileRateProvider creates a FileRateProvider and loads the rate file
+func NewFileRateProvider(path string) (*FileRateProvider, error) {
+	provider := &FileRateProvider{path: path}
+	if err := provider.Reload(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// Reload reads the rate file again. The previous table is kept if the file is invalid.
+func (provider *FileRateProvider) Reload() error {
+	data, err := os.ReadFile(provider.path)
+	if err != nil {
+		return fmt.Errorf("cannot read rate file: %w", err)
+	}
+
+	var quotes []Quote
+	if err := json.Unmarshal(data, &quotes); err != nil {
+		return fmt.Errorf("cannot parse rate file: %w", err)
+	}
+
+	static, err := NewStaticRateProvider(quotes)
+	if err != nil {
+		return err
+	}
+
+	provider.mu.Lock()
+	provider.static = static
+	provider.mu.Unlock()
+	return nil
+}
+
+// Rate returns the rate that converts an amount in from into an amount in to
+func (provider *FileRateProvider) Rate(from, to model.CurrencyType) (Rate, error) {
+	provider.mu.RLock()
+	defer provider.mu.RUnlock()
+	return provider.static.Rate(from, to)
+}
@@ -0,0 +1,77 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RateScale is the number of decimal places an exchange rate is kept with
+const RateScale = 10
+
+// ErrInvalidRate is returned when an exchange rate cannot be parsed, or it or its inverse is not positive
+// once rounded to RateScale decimal places
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is an exchange rate rounded to RateScale decimal places
+type Rate struct {
+	value *big.Rat
+}
+
+// ParseRate parses a decimal exchange rate such as "0.92".
+// The sign is checked after rounding, so a rate that rounds to zero, or whose inverse does, is invalid.
+func ParseRate(s string) (Rate, error) {
+	value, ok := new(big.Rat).SetString(s)
+	if !ok || value.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	rate := newRate(value)
+	if rate.value.Sign() <= 0 || rate.Inverse().value.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q rounds to zero", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
+
+// newRate rounds value to RateScale decimal places, so the stored rate is exactly the one used for conversion
+func newRate(value *big.Rat) Rate {
+	rounded, _ := new(big.Rat).SetString(value.FloatString(RateScale))
+	return Rate{value: rounded}
+}
+
+// Inverse returns the rate for the opposite direction
+func (rate Rate) Inverse() Rate {
+	return newRate(new(big.Rat).Inv(rate.value))
+}
+
+// Convert converts an amount in minor units of a currency with fromExponent into minor units of a currency
+// with toExponent, rounding half away from zero. The rate is quoted between major units,
+// so the amount is scaled by 10^(toExponent-fromExponent) before it is rounded.
+func (rate Rate) Convert(amount int64, fromExponent, toExponent int32) (int64, error) {
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate.value)
+	if toExponent > fromExponent {
+		converted.Mul(converted, new(big.Rat).SetInt(pow10(toExponent-fromExponent)))
+	} else if toExponent < fromExponent {
+		converted.Quo(converted, new(big.Rat).SetInt(pow10(fromExponent-toExponent)))
+	}
+	result, ok := new(big.Int).SetString(converted.FloatString(0), 10)
+	if !ok || !result.IsInt64() {
+		return 0, fmt.Errorf("converted amount of %d at rate %s overflows", amount, rate)
+	}
+	return result.Int64(), nil
+}
+
+// pow10 returns 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// String returns the rate as a decimal string without trailing zeros
+func (rate Rate) String() string {
+	if rate.value == nil {
+		return "0"
+	}
+	s := rate.value.FloatString(RateScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,144 @@
+package interest
+
+import (
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestInterest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Unit Test for interest rates")
+}
+
+var _ = Describe("Rate", func() {
+	It("Test parse rate", func() {
+		rate, err := ParseRate("0.0250")
+		Expect(err).To(BeNil())
+		Expect(rate.String()).To(Equal("0.025"))
+
+		rate, err = ParseRate("0")
+		Expect(err).To(BeNil())
+		Expect(rate.IsZero()).To(BeTrue())
+
+		for _, value := range []string{"", "abc", "-0.01", "0.0000001", "1/2", "1e-3"} {
+			_, err = ParseRate(value)
+			Expect(err).To(MatchError(ErrInvalidRate), value)
+		}
+	})
+
+	It("Test daily accrual", func() {
+		rate, err := ParseRate("0.02")
+		Expect(err).To(BeNil())
+
+		// 1000.00 at 2% a year accrues 100000 * 0.02 / 365 = 5.479452054... cents a day
+		amount, err := rate.DailyAccrual(100000)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(5479452)))
+
+		// A negative balance accrues negative interest
+		amount, err = rate.DailyAccrual(-100000)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(-5479452)))
+
+		amount, err = rate.DailyAccrual(0)
+		Expect(err).To(BeNil())
+		Expect(amount).To(BeZero())
+
+		_, err = rate.DailyAccrual(math.MaxInt64)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("Test daily accrual rounds to the nearest accrual unit", func() {
+		// 0.2 of an accrual unit is rounded down
+		rate, err := ParseRate("0.000073")
+		Expect(err).To(BeNil())
+		amount, err := rate.DailyAccrual(1)
+		Expect(err).To(BeNil())
+		Expect(amount).To(BeZero())
+
+		// 0.8 of an accrual unit is rounded up
+		rate, err = ParseRate("0.000292")
+		Expect(err).To(BeNil())
+		amount, err = rate.DailyAccrual(1)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(1)))
+		amount, err = rate.DailyAccrual(-1)
+		Expect(err).To(BeNil())
+		Expect(amount).To(Equal(int64(-1)))
+	})
+
+	It("Test round half to even", func() {
+		Expect(roundHalfEven(big.NewRat(5, 2)).Int64()).To(Equal(int64(2)))
+		Expect(roundHalfEven(big.NewRat(7, 2)).Int64()).To(Equal(int64(4)))
+		Expect(roundHalfEven(big.NewRat(-5, 2)).Int64()).To(Equal(int64(-2)))
+		Expect(roundHalfEven(big.NewRat(-1, 2)).Int64()).To(Equal(int64(0)))
+		Expect(roundHalfEven(big.NewRat(-3, 2)).Int64()).To(Equal(int64(-2)))
+		Expect(roundHalfEven(big.NewRat(11, 4)).Int64()).To(Equal(int64(3)))
+	})
+
+	It("Test split accrued interest", func() {
+		amount, carry := SplitAccrued(2_750_000)
+		Expect(amount).To(Equal(int64(2)))
+		Expect(carry).To(Equal(int64(750_000)))
+
+		// Truncated toward zero, so a charge is never larger than accrued either
+		amount, carry = SplitAccrued(-2_750_000)
+		Expect(amount).To(Equal(int64(-2)))
+		Expect(carry).To(Equal(int64(-750_000)))
+
+		amount, carry = SplitAccrued(999_999)
+		Expect(amount).To(BeZero())
+		Expect(carry).To(Equal(int64(999_999)))
+	})
+})
+
+var _ = Describe("RateTable", func() {
+	It("Test default rates", func() {
+		table, err := NewRateTable(DefaultRates)
+		Expect(err).To(BeNil())
+		Expect(table.Rate(model.AccountTypeSavings).String()).To(Equal("0.02"))
+		Expect(table.Rate(model.AccountTypeChecking).IsZero()).To(BeTrue())
+		Expect(table.Rate(model.AccountTypeLoan).IsZero()).To(BeTrue())
+	})
+
+	It("Test invalid rates", func() {
+		_, err := NewRateTable([]AccountRate{{AccountType: "credit", AnnualRate: "0.1"}})
+		Expect(err).NotTo(BeNil())
+
+		_, err = NewRateTable([]AccountRate{{AccountType: model.AccountTypeLoan, AnnualRate: "-0.1"}})
+		Expect(err).To(MatchError(ErrInvalidRate))
+
+		_, err = NewRateTable([]AccountRate{
+			{AccountType: model.AccountTypeSavings, AnnualRate: "0.01"},
+			{AccountType: model.AccountTypeSavings, AnnualRate: "0.02"},
+		})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("Test file rate table", func() {
+		dir, err := os.MkdirTemp("", "interest")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "rates.json")
+		err = os.WriteFile(path, []byte(`[{"account_type": "loan", "annual_rate": "0.08"}]`), 0o600)
+		Expect(err).To(BeNil())
+
+		table, err := NewFileRateTable(path)
+		Expect(err).To(BeNil())
+		Expect(table.Rate(model.AccountTypeLoan).String()).To(Equal("0.08"))
+		Expect(table.Rate(model.AccountTypeSavings).IsZero()).To(BeTrue())
+
+		err = os.WriteFile(path, []byte(`not json`), 0o600)
+		Expect(err).To(BeNil())
+		_, err = NewFileRateTable(path)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,69 @@
+package interest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+)
+
+// RateModel gives the annual interest rate of each account type
+type RateModel interface {
+	// Rate returns the annual rate of the account type, it is zero for a type that earns no interest
+	Rate(accountType model.AccountType) Rate
+}
+
+// AccountRate is a single entry of a rate table
+type AccountRate struct {
+	AccountType model.AccountType `json:"account_type"`
+	AnnualRate  string            `json:"annual_rate"`
+}
+
+// DefaultRates is the rate table used when no rate file is configured, only savings accounts earn interest
+var DefaultRates = []AccountRate{
+	{AccountType: model.AccountTypeSavings, AnnualRate: "0.02"},
+}
+
+// RateTable serves the rates of a fixed table, the account types missing from it earn no interest
+type RateTable struct {
+	rates map[model.AccountType]Rate
+}
+
+// NewRateTable creates a RateTable from the given rates
+func NewRateTable(rates []AccountRate) (*RateTable, error) {
+	table := &RateTable{rates: make(map[model.AccountType]Rate, len(rates))}
+	for _, entry := range rates {
+		if !entry.AccountType.IsValid() {
+			return nil, fmt.Errorf("invalid account type %q", entry.AccountType)
+		}
+		if _, ok := table.rates[entry.AccountType]; ok {
+			return nil, fmt.Errorf("account type %q has several rates", entry.AccountType)
+		}
+		rate, err := ParseRate(entry.AnnualRate)
+		if err != nil {
+			return nil, err
+		}
+		table.rates[entry.AccountType] = rate
+	}
+	return table, nil
+}
+
+// NewFileRateTable creates a RateTable from a JSON file holding a list of account rates
+func NewFileRateTable(path string) (*RateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read interest rate file: %w", err)
+	}
+
+	var rates []AccountRate
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("cannot parse interest rate file: %w", err)
+	}
+	return NewRateTable(rates)
+}
+
+// Rate returns the annual rate of the account type, it is zero for a type that earns no interest
+func (table *RateTable) Rate(accountType model.AccountType) Rate {
+	return table.rates[accountType]
+}
@@ -0,0 +1,94 @@
+package interest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// RateScale is the largest number of decimal places of an annual rate, "0.000001" is a ten-thousandth of a percent
+	RateScale = 6
+	// DaysPerYear is the day count of the actual/365 fixed convention, a leap year accrues 366 days of 1/365 each
+	DaysPerYear = 365
+	// AccrualUnit is the number of accrual units in a minor unit. Daily accruals are kept in millionths of the
+	// minor unit so that the interest of small balances is not lost to rounding before it is posted.
+	AccrualUnit = 1_000_000
+)
+
+// ErrInvalidRate is returned when an interest rate cannot be parsed, is negative or has too many decimal places
+var ErrInvalidRate = errors.New("invalid interest rate")
+
+// Rate is an annual interest rate, "0.025" is 2.5% a year
+type Rate struct {
+	value *big.Rat
+}
+
+// ParseRate parses a decimal annual rate such as "0.025".
+// Like amounts, a rate is never rounded, so a rate with more than RateScale decimal places is invalid.
+func ParseRate(s string) (Rate, error) {
+	_, fraction, _ := strings.Cut(s, ".")
+	value, ok := new(big.Rat).SetString(s)
+	if !ok || value.Sign() < 0 || len(fraction) > RateScale || strings.ContainsAny(s, "eE/") {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return Rate{value: value}, nil
+}
+
+// IsZero reports whether the rate earns no interest
+func (rate Rate) IsZero() bool {
+	return rate.value == nil || rate.value.Sign() == 0
+}
+
+// String returns the rate as a decimal string without trailing zeros
+func (rate Rate) String() string {
+	if rate.value == nil {
+		return "0"
+	}
+	s := rate.value.FloatString(RateScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// DailyAccrual returns the interest of one day on a balance in minor units, in accrual units.
+// It is balance * rate / DaysPerYear rounded half to even to the accrual unit, so the same balance and rate
+// always accrue the same amount. A negative balance accrues negative interest, which charges a loan account.
+func (rate Rate) DailyAccrual(balance int64) (int64, error) {
+	if rate.IsZero() || balance == 0 {
+		return 0, nil
+	}
+
+	accrued := new(big.Rat).Mul(new(big.Rat).SetInt64(balance), rate.value)
+	accrued.Mul(accrued, big.NewRat(AccrualUnit, DaysPerYear))
+	result := roundHalfEven(accrued)
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("interest of %d at rate %s overflows", balance, rate)
+	}
+	return result.Int64(), nil
+}
+
+// SplitAccrued splits interest in accrual units into whole minor units to post and the remainder to carry
+// to the next posting. The amount is truncated toward zero, so a posting never pays or charges more than accrued
+// and the fraction of a minor unit is kept in the carry instead of being rounded away.
+func SplitAccrued(accrued int64) (amount, carry int64) {
+	return accrued / AccrualUnit, accrued % AccrualUnit
+}
+
+// roundHalfEven rounds a rational number to the nearest integer, and a tie to the even integer
+func roundHalfEven(value *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	// Compare twice the remainder with the denominator to tell which integer is nearer
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	cmp := twice.Cmp(value.Denom())
+	if cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1) {
+		if remainder.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
 	"github.com/Petatron/bank-simulator-backend/api"
 	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/interest"
+	"github.com/Petatron/bank-simulator-backend/worker"
 	_ "github.com/lib/pq"
 	"log"
+	"os"
+	"time"
 )
 
 func main() {
@@ -21,13 +28,70 @@ func main() {
 	}
 
 	store := db.NewStore(conn)
+	if len(os.Args) > 2 && os.Args[1] == "ledger" && os.Args[2] == "verify" {
+		os.Exit(verifyLedger(store, os.Args[3:]))
+	}
+
+	if config.HoldReleaseInterval > 0 {
+		go worker.NewHoldReleaser(store, config.HoldReleaseInterval).Run(context.Background())
+	}
+	if config.ScheduledTransferInterval > 0 {
+		go worker.NewScheduledTransferRunner(store, config.ScheduledTransferInterval).Run(context.Background())
+	}
+	if config.InterestAccrualInterval > 0 {
+		rates, err := newInterestRateModel(config)
+		if err != nil {
+			log.Fatal("Cannot create interest rate model with error: ", err)
+		}
+		go worker.NewInterestAccruer(store, rates, worker.SystemClock, config.InterestAccrualInterval).Run(context.Background())
+	}
+
 	server, err := api.NewServer(config, store)
 	if err != nil {
 		log.Fatal("Cannot create server with error: ", err)
 	}
+	// Only a registry backed by the database has changes from other instances to pick up
+	if registry, ok := server.Currencies().(worker.Reloader); ok && config.CurrencyReloadInterval > 0 {
+		go worker.NewCurrencyReloader(registry, config.CurrencyReloadInterval).Run(context.Background())
+	}
 
 	err = server.Start(config.ServerAddress)
 	if err != nil {
 		log.Fatal("Cannot start server with error: ", err)
 	}
 }
+
+// newInterestRateModel loads the interest rates from the configured file, or falls back to the default rate table.
+func newInterestRateModel(config util.Config) (interest.RateModel, error) {
+	if config.InterestRatesFile != "" {
+		return interest.NewFileRateTable(config.InterestRatesFile)
+	}
+	return interest.NewRateTable(interest.DefaultRates)
+}
+
+// verifyLedger runs the "ledger verify" command and returns its exit code.
+// The report is written to stdout, and the exit code is 1 if the ledger has discrepancies.
+func verifyLedger(store db.Store, args []string) int {
+	flags := flag.NewFlagSet("ledger verify", flag.ExitOnError)
+	full := flags.Bool("full", false, "verify the whole ledger instead of resuming from the latest checkpoint")
+	_ = flags.Parse(args)
+
+	report, err := store.VerifyLedger(context.Background(), db.VerifyLedgerParams{
+		Full: *full,
+		Now:  time.Now(),
+	})
+	if err != nil {
+		log.Fatal("Cannot verify ledger with error: ", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("Cannot write ledger report with error: ", err)
+	}
+
+	if !report.Balanced() {
+		return 1
+	}
+	return 0
+}
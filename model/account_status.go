@@ -0,0 +1,19 @@
+package model
+
+type AccountStatus string
+
+// Account statuses. An account starts active and a closed account stays closed.
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+// IsValid check if the account status is supported.
+func (s AccountStatus) IsValid() bool {
+	switch s {
+	case AccountStatusActive, AccountStatusFrozen, AccountStatusClosed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package model_test
+
+import (
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccountStatus", func() {
+	It("Test valid account status", func() {
+		Expect(model.AccountStatusActive.IsValid()).To(BeTrue())
+		Expect(model.AccountStatusFrozen.IsValid()).To(BeTrue())
+		Expect(model.AccountStatusClosed.IsValid()).To(BeTrue())
+	})
+
+	It("Test invalid account status", func() {
+		Expect(model.AccountStatus("suspended").IsValid()).To(BeFalse())
+	})
+})
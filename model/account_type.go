@@ -0,0 +1,22 @@
+package model
+
+type AccountType string
+
+// Account types. Every type holds money the same way, the type tells what the account is used for.
+const (
+	AccountTypeChecking AccountType = "checking"
+	AccountTypeSavings  AccountType = "savings"
+	AccountTypeLoan     AccountType = "loan"
+)
+
+// AccountTypes lists every account type
+var AccountTypes = []AccountType{AccountTypeChecking, AccountTypeSavings, AccountTypeLoan}
+
+// IsValid check if the account type is supported.
+func (t AccountType) IsValid() bool {
+	switch t {
+	case AccountTypeChecking, AccountTypeSavings, AccountTypeLoan:
+		return true
+	}
+	return false
+}
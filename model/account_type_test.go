@@ -0,0 +1,19 @@
+package model_test
+
+import (
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccountType", func() {
+	It("Test valid account type", func() {
+		Expect(model.AccountTypeChecking.IsValid()).To(BeTrue())
+		Expect(model.AccountTypeSavings.IsValid()).To(BeTrue())
+		Expect(model.AccountTypeLoan.IsValid()).To(BeTrue())
+	})
+
+	It("Test invalid account type", func() {
+		Expect(model.AccountType("brokerage").IsValid()).To(BeFalse())
+	})
+})
@@ -1,25 +1,25 @@
 package model
 
+// CurrencyType is an ISO 4217 alphabetic currency code.
+// Which currencies are supported is decided by the currency registry, not by this type.
 type CurrencyType string
 
-// Currency Types supported by the system.
+// Currency Types seeded in the currency registry.
 const (
 	USD CurrencyType = "USD"
 	EUR CurrencyType = "EUR"
 	CAD CurrencyType = "CAD"
 )
 
-var CurrencyMap = map[CurrencyType]string{
-	USD: "USD",
-	EUR: "EUR",
-	CAD: "CAD",
-}
-
-// IsValid check if the currency type is supported.
+// IsValid check if the currency type is a well-formed ISO 4217 code, made of three upper case letters.
 func (c CurrencyType) IsValid() bool {
-	switch c {
-	case USD, EUR, CAD:
-		return true
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
 	}
-	return false
+	return true
 }
@@ -18,9 +18,12 @@ var _ = Describe("CurrencyType", func() {
 		Expect(model.USD.IsValid()).To(BeTrue())
 		Expect(model.EUR.IsValid()).To(BeTrue())
 		Expect(model.CAD.IsValid()).To(BeTrue())
+		Expect(model.CurrencyType("JPY").IsValid()).To(BeTrue())
 	})
 
 	It("Test invalid currency type", func() {
 		Expect(model.CurrencyType("INVALID").IsValid()).To(BeFalse())
+		Expect(model.CurrencyType("usd").IsValid()).To(BeFalse())
+		Expect(model.CurrencyType("").IsValid()).To(BeFalse())
 	})
 })
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxMoneyExponent is the largest exponent of a Money, 10^18 is the largest power of ten that fits an int64
+const MaxMoneyExponent = 18
+
+var (
+	// ErrInvalidMoney is returned when a decimal string is not a valid amount of its currency
+	ErrInvalidMoney = errors.New("invalid money amount")
+	// ErrMoneyOverflow is returned when an amount does not fit in an int64 number of minor units
+	ErrMoneyOverflow = errors.New("money amount overflows")
+	// ErrCurrencyMismatch is returned when amounts in different currencies are combined
+	ErrCurrencyMismatch = errors.New("money amounts are in different currencies")
+)
+
+// Money is an amount in the minor unit of its currency, such as cents for USD or yen for JPY.
+// Exponent is the number of digits of the minor unit, it is defined for each currency by the currency registry.
+type Money struct {
+	Amount   int64        `json:"amount"`
+	Currency CurrencyType `json:"currency"`
+	Exponent int32        `json:"exponent"`
+}
+
+// NewMoney creates a Money from an amount in minor units
+func NewMoney(amount int64, currency CurrencyType, exponent int32) Money {
+	return Money{Amount: amount, Currency: currency, Exponent: exponent}
+}
+
+// ParseMoney parses a decimal string such as "12.34" or "-5" in the major unit of the currency.
+// It never rounds, so a string with more fraction digits than the exponent is invalid.
+func ParseMoney(value string, currency CurrencyType, exponent int32) (Money, error) {
+	if exponent < 0 || exponent > MaxMoneyExponent {
+		return Money{}, fmt.Errorf("%w: exponent %d is out of range", ErrInvalidMoney, exponent)
+	}
+
+	digits, negative := strings.CutPrefix(value, "-")
+	whole, fraction, hasFraction := strings.Cut(digits, ".")
+	if !isDigits(whole) || (hasFraction && !isDigits(fraction)) {
+		return Money{}, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidMoney, value)
+	}
+	if len(fraction) > int(exponent) {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrInvalidMoney, value, exponent, currency)
+	}
+
+	// Pad the fraction to the exponent, "12.3" in cents is 1230
+	minor := whole + fraction + strings.Repeat("0", int(exponent)-len(fraction))
+	amount, err := strconv.ParseInt(minor, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyOverflow, value)
+	}
+	if negative {
+		amount = -amount
+	}
+	return NewMoney(amount, currency, exponent), nil
+}
+
+// String formats the amount as a decimal string in the major unit, with exactly Exponent decimal places
+func (money Money) String() string {
+	digits := strconv.FormatUint(absAmount(money.Amount), 10)
+	sign := ""
+	if money.Amount < 0 {
+		sign = "-"
+	}
+	if money.Exponent <= 0 {
+		return sign + digits
+	}
+
+	exponent := int(money.Exponent)
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	split := len(digits) - exponent
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// Add returns the sum of two amounts in the same currency
+func (money Money) Add(other Money) (Money, error) {
+	if err := money.checkSameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := money.Amount + other.Amount
+	if (other.Amount > 0 && sum < money.Amount) || (other.Amount < 0 && sum > money.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrMoneyOverflow, money, other)
+	}
+	return NewMoney(sum, money.Currency, money.Exponent), nil
+}
+
+// Sub returns the difference of two amounts in the same currency
+func (money Money) Sub(other Money) (Money, error) {
+	if err := money.checkSameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	difference := money.Amount - other.Amount
+	if (other.Amount > 0 && difference > money.Amount) || (other.Amount < 0 && difference < money.Amount) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrMoneyOverflow, money, other)
+	}
+	return NewMoney(difference, money.Currency, money.Exponent), nil
+}
+
+// Mul returns the amount multiplied by a whole factor
+func (money Money) Mul(factor int64) (Money, error) {
+	product := money.Amount * factor
+	if money.Amount != 0 && (product/money.Amount != factor ||
+		(money.Amount == -1 && factor == math.MinInt64) ||
+		(factor == -1 && money.Amount == math.MinInt64)) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrMoneyOverflow, money, factor)
+	}
+	return NewMoney(product, money.Currency, money.Exponent), nil
+}
+
+// Neg returns the opposite amount
+func (money Money) Neg() (Money, error) {
+	return money.Mul(-1)
+}
+
+// checkSameCurrency returns an error unless both amounts are in the same currency
+func (money Money) checkSameCurrency(other Money) error {
+	if money.Currency != other.Currency || money.Exponent != other.Exponent {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, money.Currency, other.Currency)
+	}
+	return nil
+}
+
+// isDigits checks if the string is a non-empty run of ASCII digits
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// absAmount returns the absolute value of an amount, math.MinInt64 included
+func absAmount(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+	return uint64(amount)
+}
@@ -0,0 +1,101 @@
+package model_test
+
+import (
+	"math"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Money", func() {
+	It("Test parse decimal strings", func() {
+		testCases := []struct {
+			value    string
+			exponent int32
+			amount   int64
+		}{
+			{"12.34", 2, 1234},
+			{"12.3", 2, 1230},
+			{"12", 2, 1200},
+			{"0.05", 2, 5},
+			{"-7.50", 2, -750},
+			{"1500", 0, 1500},
+			{"1.234", 3, 1234},
+			{"92233720368547758.07", 2, math.MaxInt64},
+		}
+		for _, tc := range testCases {
+			money, err := model.ParseMoney(tc.value, model.USD, tc.exponent)
+			Expect(err).To(BeNil(), tc.value)
+			Expect(money.Amount).To(Equal(tc.amount), tc.value)
+			Expect(money.Currency).To(Equal(model.USD))
+		}
+	})
+
+	It("Test parse invalid decimal strings", func() {
+		for _, value := range []string{"", "-", ".5", "1.", "1.2.3", "1,00", "+1", "1e3", " 1", "abc"} {
+			_, err := model.ParseMoney(value, model.USD, 2)
+			Expect(err).To(MatchError(model.ErrInvalidMoney), value)
+		}
+
+		// Amounts are never rounded
+		_, err := model.ParseMoney("12.345", model.USD, 2)
+		Expect(err).To(MatchError(model.ErrInvalidMoney))
+		_, err = model.ParseMoney("1.5", "JPY", 0)
+		Expect(err).To(MatchError(model.ErrInvalidMoney))
+
+		_, err = model.ParseMoney("92233720368547758.08", model.USD, 2)
+		Expect(err).To(MatchError(model.ErrMoneyOverflow))
+	})
+
+	It("Test format decimal strings", func() {
+		Expect(model.NewMoney(1234, model.USD, 2).String()).To(Equal("12.34"))
+		Expect(model.NewMoney(5, model.USD, 2).String()).To(Equal("0.05"))
+		Expect(model.NewMoney(0, model.USD, 2).String()).To(Equal("0.00"))
+		Expect(model.NewMoney(-750, model.USD, 2).String()).To(Equal("-7.50"))
+		Expect(model.NewMoney(1500, "JPY", 0).String()).To(Equal("1500"))
+		Expect(model.NewMoney(math.MinInt64, model.USD, 2).String()).To(Equal("-92233720368547758.08"))
+	})
+
+	It("Test arithmetic", func() {
+		a := model.NewMoney(1050, model.USD, 2)
+		b := model.NewMoney(250, model.USD, 2)
+
+		sum, err := a.Add(b)
+		Expect(err).To(BeNil())
+		Expect(sum.String()).To(Equal("13.00"))
+
+		difference, err := b.Sub(a)
+		Expect(err).To(BeNil())
+		Expect(difference.String()).To(Equal("-8.00"))
+
+		product, err := b.Mul(3)
+		Expect(err).To(BeNil())
+		Expect(product.Amount).To(Equal(int64(750)))
+
+		negative, err := a.Neg()
+		Expect(err).To(BeNil())
+		Expect(negative.Amount).To(Equal(int64(-1050)))
+
+		_, err = a.Add(model.NewMoney(250, model.EUR, 2))
+		Expect(err).To(MatchError(model.ErrCurrencyMismatch))
+	})
+
+	It("Test arithmetic overflow", func() {
+		largest := model.NewMoney(math.MaxInt64, model.USD, 2)
+		smallest := model.NewMoney(math.MinInt64, model.USD, 2)
+		one := model.NewMoney(1, model.USD, 2)
+
+		_, err := largest.Add(one)
+		Expect(err).To(MatchError(model.ErrMoneyOverflow))
+		_, err = smallest.Sub(one)
+		Expect(err).To(MatchError(model.ErrMoneyOverflow))
+		_, err = largest.Mul(2)
+		Expect(err).To(MatchError(model.ErrMoneyOverflow))
+		_, err = smallest.Neg()
+		Expect(err).To(MatchError(model.ErrMoneyOverflow))
+
+		_, err = largest.Sub(one)
+		Expect(err).To(BeNil())
+	})
+})
@@ -0,0 +1,19 @@
+package model
+
+type Role string
+
+// Roles a user can have. Every new user is a customer.
+const (
+	RoleCustomer Role = "customer"
+	RoleTeller   Role = "teller"
+	RoleAdmin    Role = "admin"
+)
+
+// IsValid check if the role is supported.
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleCustomer, RoleTeller, RoleAdmin:
+		return true
+	}
+	return false
+}
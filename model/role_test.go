@@ -0,0 +1,19 @@
+package model_test
+
+import (
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Role", func() {
+	It("Test valid role", func() {
+		Expect(model.RoleCustomer.IsValid()).To(BeTrue())
+		Expect(model.RoleTeller.IsValid()).To(BeTrue())
+		Expect(model.RoleAdmin.IsValid()).To(BeTrue())
+	})
+
+	It("Test invalid role", func() {
+		Expect(model.Role("superuser").IsValid()).To(BeFalse())
+	})
+})
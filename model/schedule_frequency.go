@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+type ScheduleFrequency string
+
+// Schedule frequencies of scheduled transfers. A once schedule has a single occurrence at its start.
+const (
+	ScheduleFrequencyOnce    ScheduleFrequency = "once"
+	ScheduleFrequencyDaily   ScheduleFrequency = "daily"
+	ScheduleFrequencyWeekly  ScheduleFrequency = "weekly"
+	ScheduleFrequencyMonthly ScheduleFrequency = "monthly"
+)
+
+// IsValid check if the schedule frequency is supported.
+func (f ScheduleFrequency) IsValid() bool {
+	switch f {
+	case ScheduleFrequencyOnce, ScheduleFrequencyDaily, ScheduleFrequencyWeekly, ScheduleFrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+// Occurrence returns the time of the nth occurrence of a schedule that starts at start, counting from zero.
+// It returns false if the schedule has no such occurrence.
+// Monthly occurrences keep the day of the month of start, or use the last day of shorter months.
+func (f ScheduleFrequency) Occurrence(start time.Time, n int) (time.Time, bool) {
+	if n < 0 {
+		return time.Time{}, false
+	}
+	switch f {
+	case ScheduleFrequencyOnce:
+		return start, n == 0
+	case ScheduleFrequencyDaily:
+		return start.AddDate(0, 0, n), true
+	case ScheduleFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n), true
+	case ScheduleFrequencyMonthly:
+		return addMonths(start, n), true
+	}
+	return time.Time{}, false
+}
+
+// addMonths adds n months to t without overflowing into the following month
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	firstOfMonth := time.Date(year, month+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if lastDay := firstOfMonth.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
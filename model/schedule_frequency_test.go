@@ -0,0 +1,61 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScheduleFrequency", func() {
+	start := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+
+	It("Test valid schedule frequency", func() {
+		Expect(model.ScheduleFrequencyOnce.IsValid()).To(BeTrue())
+		Expect(model.ScheduleFrequencyDaily.IsValid()).To(BeTrue())
+		Expect(model.ScheduleFrequencyWeekly.IsValid()).To(BeTrue())
+		Expect(model.ScheduleFrequencyMonthly.IsValid()).To(BeTrue())
+		Expect(model.ScheduleFrequency("hourly").IsValid()).To(BeFalse())
+	})
+
+	It("Test once schedule has a single occurrence", func() {
+		occurrence, ok := model.ScheduleFrequencyOnce.Occurrence(start, 0)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(start))
+
+		_, ok = model.ScheduleFrequencyOnce.Occurrence(start, 1)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Test daily and weekly occurrences", func() {
+		occurrence, ok := model.ScheduleFrequencyDaily.Occurrence(start, 2)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(time.Date(2024, time.February, 2, 9, 30, 0, 0, time.UTC)))
+
+		occurrence, ok = model.ScheduleFrequencyWeekly.Occurrence(start, 1)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(time.Date(2024, time.February, 7, 9, 30, 0, 0, time.UTC)))
+	})
+
+	It("Test monthly occurrences keep the day of the month", func() {
+		occurrence, ok := model.ScheduleFrequencyMonthly.Occurrence(start, 1)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(time.Date(2024, time.February, 29, 9, 30, 0, 0, time.UTC)))
+
+		occurrence, ok = model.ScheduleFrequencyMonthly.Occurrence(start, 2)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(time.Date(2024, time.March, 31, 9, 30, 0, 0, time.UTC)))
+
+		occurrence, ok = model.ScheduleFrequencyMonthly.Occurrence(start, 12)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(time.Date(2025, time.January, 31, 9, 30, 0, 0, time.UTC)))
+	})
+
+	It("Test invalid occurrence", func() {
+		_, ok := model.ScheduleFrequencyDaily.Occurrence(start, -1)
+		Expect(ok).To(BeFalse())
+		_, ok = model.ScheduleFrequency("hourly").Occurrence(start, 0)
+		Expect(ok).To(BeFalse())
+	})
+})
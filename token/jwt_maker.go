@@ -0,0 +1,130 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"time"
+)
+
+const minSecretKeySize = 32
+
+// JWTMaker is a JSON Web Token maker
+type JWTMaker struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// jwtClaims maps a Payload onto the registered JWT claims so other services can read our tokens
+type jwtClaims struct {
+	Username string     `json:"username"`
+	Role     model.Role `json:"role"`
+	Type     TokenType  `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+// NewJWTMaker creates a new JWTMaker that signs tokens with HS256 and a shared secret key
+func NewJWTMaker(secretKey string) (Maker, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+	maker := &JWTMaker{
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secretKey),
+		verifyKey: []byte(secretKey),
+	}
+	return maker, nil
+}
+
+// NewEdDSAJWTMaker creates a new JWTMaker that signs tokens with EdDSA and a PEM encoded Ed25519 private key
+func NewEdDSAJWTMaker(privateKeyPEM []byte) (Maker, error) {
+	privateKey, err := jwt.ParseEdPrivateKeyFromPEM(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EdDSA private key: %w", err)
+	}
+	maker := &JWTMaker{
+		method:    jwt.SigningMethodEdDSA,
+		signKey:   privateKey,
+		verifyKey: privateKey.(ed25519.PrivateKey).Public(),
+	}
+	return maker, nil
+}
+
+// NewRS256JWTMaker creates a new JWTMaker that signs tokens with RS256 and a PEM encoded RSA private key
+func NewRS256JWTMaker(privateKeyPEM []byte) (Maker, error) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RS256 private key: %w", err)
+	}
+	maker := &JWTMaker{
+		method:    jwt.SigningMethodRS256,
+		signKey:   privateKey,
+		verifyKey: &privateKey.PublicKey,
+	}
+	return maker, nil
+}
+
+// CreateToken creates a new token of the given type for a specific username, role and duration
+func (maker *JWTMaker) CreateToken(username string, role model.Role, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
+	if err != nil {
+		return "", nil, err
+	}
+
+	claims := &jwtClaims{
+		Username: payload.Username,
+		Role:     payload.Role,
+		Type:     payload.Type,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.Username,
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(maker.method, claims).SignedString(maker.signKey)
+	return token, payload, err
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		return maker.verifyKey, nil
+	}
+
+	claims := &jwtClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, keyFunc,
+		jwt.WithValidMethods([]string{maker.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			return nil, ErrExpiredToken
+		case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+			return nil, ErrNotYetValid
+		}
+		return nil, ErrInvalidToken
+	}
+
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil || claims.IssuedAt == nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{
+		ID:        tokenID,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		Type:      claims.Type,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiredAt: claims.ExpiresAt.Time,
+	}
+	return payload, nil
+}
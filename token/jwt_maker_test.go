@@ -0,0 +1,175 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+func newEdDSAPrivateKeyPEM() []byte {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).To(BeNil())
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	Expect(err).To(BeNil())
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func newRSAPrivateKeyPEM() []byte {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).To(BeNil())
+	der := x509.MarshalPKCS1PrivateKey(privateKey)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der})
+}
+
+var _ = Describe("JWT maker tests", func() {
+	makers := []struct {
+		name     string
+		newMaker func() (Maker, error)
+	}{
+		{
+			name: "HS256",
+			newMaker: func() (Maker, error) {
+				return NewJWTMaker(util.GetRandomStringWithLength(32))
+			},
+		},
+		{
+			name: "EdDSA",
+			newMaker: func() (Maker, error) {
+				return NewEdDSAJWTMaker(newEdDSAPrivateKeyPEM())
+			},
+		},
+		{
+			name: "RS256",
+			newMaker: func() (Maker, error) {
+				return NewRS256JWTMaker(newRSAPrivateKeyPEM())
+			},
+		},
+	}
+
+	for i := range makers {
+		m := makers[i]
+
+		It("Test create and verify token with "+m.name, func() {
+			maker, err := m.newMaker()
+			Expect(err).To(BeNil())
+
+			username := util.GetRandomOwnerName()
+			duration := time.Minute
+
+			issuedAt := time.Now()
+			expiredAt := issuedAt.Add(duration)
+
+			token, tokenPayload, err := maker.CreateToken(username, model.RoleTeller, TokenTypeAccess, duration)
+			Expect(err).To(BeNil())
+			Expect(token).NotTo(BeEmpty())
+			Expect(tokenPayload).NotTo(BeNil())
+
+			payload, err := maker.VerifyToken(token)
+			Expect(err).To(BeNil())
+			Expect(payload).NotTo(BeNil())
+			Expect(payload.ID).To(Equal(tokenPayload.ID))
+			Expect(payload.Username).To(Equal(username))
+			Expect(payload.Role).To(Equal(model.RoleTeller))
+			Expect(payload.Type).To(Equal(TokenTypeAccess))
+			Expect(payload.IssuedAt).To(BeTemporally("~", issuedAt, time.Second))
+			Expect(payload.ExpiredAt).To(BeTemporally("~", expiredAt, time.Second))
+		})
+
+		It("Test expired token with "+m.name, func() {
+			maker, err := m.newMaker()
+			Expect(err).To(BeNil())
+
+			token, _, err := maker.CreateToken(util.GetRandomOwnerName(), model.RoleCustomer, TokenTypeAccess, -time.Minute)
+			Expect(err).To(BeNil())
+			Expect(token).NotTo(BeEmpty())
+
+			payload, err := maker.VerifyToken(token)
+			Expect(err).To(Equal(ErrExpiredToken))
+			Expect(payload).To(BeNil())
+		})
+
+		It("Test token signed by another key with "+m.name, func() {
+			maker, err := m.newMaker()
+			Expect(err).To(BeNil())
+			otherMaker, err := m.newMaker()
+			Expect(err).To(BeNil())
+
+			token, _, err := otherMaker.CreateToken(util.GetRandomOwnerName(), model.RoleCustomer, TokenTypeAccess, time.Minute)
+			Expect(err).To(BeNil())
+
+			payload, err := maker.VerifyToken(token)
+			Expect(err).To(Equal(ErrInvalidToken))
+			Expect(payload).To(BeNil())
+		})
+	}
+
+	It("Test malformed token", func() {
+		maker, err := NewJWTMaker(util.GetRandomStringWithLength(32))
+		Expect(err).To(BeNil())
+
+		payload, err := maker.VerifyToken("not.a.token")
+		Expect(err).To(Equal(ErrInvalidToken))
+		Expect(payload).To(BeNil())
+	})
+
+	It("Test token not valid yet", func() {
+		secretKey := util.GetRandomStringWithLength(32)
+		maker, err := NewJWTMaker(secretKey)
+		Expect(err).To(BeNil())
+
+		claims := jwt.MapClaims{
+			"jti":      "8d1b2a70-6d1f-4f5a-9f8e-2f0c8a7e3b11",
+			"username": util.GetRandomOwnerName(),
+			"iat":      time.Now().Add(time.Minute).Unix(),
+			"exp":      time.Now().Add(2 * time.Minute).Unix(),
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+		Expect(err).To(BeNil())
+
+		payload, err := maker.VerifyToken(token)
+		Expect(err).To(Equal(ErrNotYetValid))
+		Expect(payload).To(BeNil())
+	})
+
+	It("Test token with none algorithm", func() {
+		maker, err := NewJWTMaker(util.GetRandomStringWithLength(32))
+		Expect(err).To(BeNil())
+
+		claims := jwt.MapClaims{
+			"jti":      "8d1b2a70-6d1f-4f5a-9f8e-2f0c8a7e3b11",
+			"username": util.GetRandomOwnerName(),
+			"iat":      time.Now().Unix(),
+			"exp":      time.Now().Add(time.Minute).Unix(),
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		Expect(err).To(BeNil())
+
+		payload, err := maker.VerifyToken(token)
+		Expect(err).To(Equal(ErrInvalidToken))
+		Expect(payload).To(BeNil())
+	})
+
+	It("Test invalid key size", func() {
+		maker, err := NewJWTMaker(util.GetRandomStringWithLength(minSecretKeySize - 1))
+		Expect(err).NotTo(BeNil())
+		Expect(maker).To(BeNil())
+	})
+
+	It("Test invalid private key", func() {
+		maker, err := NewEdDSAJWTMaker([]byte("not a key"))
+		Expect(err).NotTo(BeNil())
+		Expect(maker).To(BeNil())
+
+		maker, err = NewRS256JWTMaker(newEdDSAPrivateKeyPEM())
+		Expect(err).NotTo(BeNil())
+		Expect(maker).To(BeNil())
+	})
+})
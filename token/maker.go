@@ -1,11 +1,14 @@
 package token
 
-import "time"
+import (
+	"github.com/Petatron/bank-simulator-backend/model"
+	"time"
+)
 
 // Maker is an interface that creates and verifies tokens
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, error)
+	// CreateToken creates a new token of the given type for a specific username, role and duration
+	CreateToken(username string, role model.Role, tokenType TokenType, duration time.Duration) (string, *Payload, error)
 
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
@@ -2,6 +2,7 @@ package token
 
 import (
 	"fmt"
+	"github.com/Petatron/bank-simulator-backend/model"
 	"github.com/aead/chacha20poly1305"
 	"github.com/o1egl/paseto"
 	"time"
@@ -25,14 +26,15 @@ func NewPasetoMaker(SymmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, error) {
-	payload, err := NewPayload(username, duration)
+// CreateToken creates a new token of the given type for a specific username, role and duration
+func (maker *PasetoMaker) CreateToken(username string, role model.Role, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	return token, payload, err
 }
 
 // VerifyToken checks if the token is valid or not
@@ -41,7 +43,7 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 
 	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
 	if err != nil {
-		return nil, ErrExpiredToken
+		return nil, ErrInvalidToken
 	}
 
 	err = payload.Valid()
@@ -2,6 +2,8 @@ package token
 
 import (
 	"github.com/Petatron/bank-simulator-backend/db/util"
+	"github.com/Petatron/bank-simulator-backend/model"
+	"github.com/o1egl/paseto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"testing"
@@ -24,14 +26,18 @@ var _ = Describe("PASETO maker tests", func() {
 		issuedAt := time.Now()
 		expiredAt := issuedAt.Add(duration)
 
-		token, err := maker.CreateToken(username, duration)
+		token, tokenPayload, err := maker.CreateToken(username, model.RoleTeller, TokenTypeAccess, duration)
 		Expect(err).To(BeNil())
 		Expect(token).NotTo(BeEmpty())
+		Expect(tokenPayload).NotTo(BeNil())
 
 		payload, err := maker.VerifyToken(token)
 		Expect(err).To(BeNil())
 		Expect(payload).NotTo(BeNil())
+		Expect(payload.ID).To(Equal(tokenPayload.ID))
 		Expect(payload.Username).To(Equal(username))
+		Expect(payload.Role).To(Equal(model.RoleTeller))
+		Expect(payload.Type).To(Equal(TokenTypeAccess))
 		Expect(payload.IssuedAt).To(BeTemporally("~", issuedAt))
 		Expect(payload.ExpiredAt).To(BeTemporally("~", expiredAt))
 	})
@@ -40,7 +46,7 @@ var _ = Describe("PASETO maker tests", func() {
 		maker, err := NewPasetoMaker(util.GetRandomStringWithLength(32))
 		Expect(err).To(BeNil())
 
-		token, err := maker.CreateToken(util.GetRandomOwnerName(), -time.Minute)
+		token, _, err := maker.CreateToken(util.GetRandomOwnerName(), model.RoleCustomer, TokenTypeAccess, -time.Minute)
 		Expect(err).To(BeNil())
 		Expect(token).NotTo(BeEmpty())
 
@@ -48,4 +54,31 @@ var _ = Describe("PASETO maker tests", func() {
 		Expect(err).To(Equal(ErrExpiredToken))
 		Expect(payload).To(BeNil())
 	})
+
+	It("Test malformed token", func() {
+		maker, err := NewPasetoMaker(util.GetRandomStringWithLength(32))
+		Expect(err).To(BeNil())
+
+		payload, err := maker.VerifyToken("v2.local.malformed")
+		Expect(err).To(Equal(ErrInvalidToken))
+		Expect(payload).To(BeNil())
+	})
+
+	It("Test token not valid yet", func() {
+		symmetricKey := util.GetRandomStringWithLength(32)
+		maker, err := NewPasetoMaker(symmetricKey)
+		Expect(err).To(BeNil())
+
+		payload, err := NewPayload(util.GetRandomOwnerName(), model.RoleCustomer, TokenTypeAccess, time.Minute)
+		Expect(err).To(BeNil())
+		payload.IssuedAt = payload.IssuedAt.Add(time.Minute)
+		payload.ExpiredAt = payload.ExpiredAt.Add(time.Minute)
+
+		token, err := paseto.NewV2().Encrypt([]byte(symmetricKey), payload, nil)
+		Expect(err).To(BeNil())
+
+		payload, err = maker.VerifyToken(token)
+		Expect(err).To(Equal(ErrNotYetValid))
+		Expect(payload).To(BeNil())
+	})
 })
@@ -2,23 +2,44 @@ package token
 
 import (
 	"errors"
+	"fmt"
+	"github.com/Petatron/bank-simulator-backend/model"
 	"github.com/google/uuid"
 	"time"
 )
 
-// ErrExpiredToken is returned when the token is expired
-var ErrExpiredToken = errors.New("token has expired")
+// Different types of error returned by the VerifyToken function and the RevocationStore
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+	ErrNotYetValid  = errors.New("token is not valid yet")
+	ErrRevoked      = errors.New("token has been revoked")
+	// ErrWrongTokenType is returned when a refresh token is used as an access token, or the other way around
+	ErrWrongTokenType = errors.New("token has the wrong type")
+)
+
+// TokenType tells what a token may be used for
+type TokenType string
+
+const (
+	// TokenTypeAccess is the type of the short-lived tokens that authorize API requests
+	TokenTypeAccess TokenType = "access"
+	// TokenTypeRefresh is the type of the long-lived tokens that are only exchanged for new access tokens
+	TokenTypeRefresh TokenType = "refresh"
+)
 
 // Payload contains the payload data of the token
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	IssuedAt  time.Time `json:"issued_at"`
-	ExpiredAt time.Time `json:"expired_at"`
+	ID        uuid.UUID  `json:"id"`
+	Username  string     `json:"username"`
+	Role      model.Role `json:"role"`
+	Type      TokenType  `json:"token_type"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	ExpiredAt time.Time  `json:"expired_at"`
 }
 
 // NewPayload creates a new payload for a token
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+func NewPayload(username string, role model.Role, tokenType TokenType, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -27,6 +48,8 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
+		Type:      tokenType,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -35,8 +58,20 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 
 // Valid checks if the token payload is valid or not
 func (payload *Payload) Valid() error {
-	if time.Now().After(payload.ExpiredAt) {
+	now := time.Now()
+	if now.Before(payload.IssuedAt) {
+		return ErrNotYetValid
+	}
+	if now.After(payload.ExpiredAt) {
 		return ErrExpiredToken
 	}
 	return nil
 }
+
+// CheckType returns ErrWrongTokenType unless the token has the expected type
+func (payload *Payload) CheckType(expected TokenType) error {
+	if payload.Type != expected {
+		return fmt.Errorf("%w: expected %s token", ErrWrongTokenType, expected)
+	}
+	return nil
+}
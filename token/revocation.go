@@ -0,0 +1,102 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/google/uuid"
+)
+
+// RevocationStore records the IDs of revoked tokens until the tokens expire
+type RevocationStore interface {
+	// Revoke marks the token with the given payload ID as revoked until it expires
+	Revoke(ctx context.Context, id uuid.UUID, expiresAt time.Time) error
+
+	// IsRevoked checks if the token with the given payload ID has been revoked
+	IsRevoked(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// MemoryRevocationStore is a RevocationStore that keeps revoked IDs in memory.
+// Revocations are lost on restart and are not shared between server instances.
+type MemoryRevocationStore struct {
+	mu      sync.Mutex
+	revoked map[uuid.UUID]time.Time
+}
+
+// NewMemoryRevocationStore creates a new MemoryRevocationStore
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{revoked: make(map[uuid.UUID]time.Time)}
+}
+
+// Revoke marks the token with the given payload ID as revoked until it expires
+func (store *MemoryRevocationStore) Revoke(_ context.Context, id uuid.UUID, expiresAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.purge(time.Now())
+	store.revoked[id] = expiresAt
+	return nil
+}
+
+// IsRevoked checks if the token with the given payload ID has been revoked
+func (store *MemoryRevocationStore) IsRevoked(_ context.Context, id uuid.UUID) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	_, ok := store.revoked[id]
+	return ok, nil
+}
+
+// Len returns the number of revoked IDs that are still kept
+func (store *MemoryRevocationStore) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return len(store.revoked)
+}
+
+// purge drops the IDs of tokens that have expired, since they are rejected anyway
+func (store *MemoryRevocationStore) purge(now time.Time) {
+	for id, expiresAt := range store.revoked {
+		if now.After(expiresAt) {
+			delete(store.revoked, id)
+		}
+	}
+}
+
+// PostgresRevocationStore is a RevocationStore backed by the revoked_tokens table
+type PostgresRevocationStore struct {
+	querier db.Querier
+}
+
+// NewPostgresRevocationStore creates a new PostgresRevocationStore
+func NewPostgresRevocationStore(querier db.Querier) *PostgresRevocationStore {
+	return &PostgresRevocationStore{querier: querier}
+}
+
+// Revoke marks the token with the given payload ID as revoked until it expires.
+// Entries of tokens that have already expired are purged on the way.
+func (store *PostgresRevocationStore) Revoke(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	err := store.querier.RevokeToken(ctx, db.RevokeTokenParams{
+		ID:        id,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Purge(ctx)
+	return err
+}
+
+// IsRevoked checks if the token with the given payload ID has been revoked
+func (store *PostgresRevocationStore) IsRevoked(ctx context.Context, id uuid.UUID) (bool, error) {
+	return store.querier.IsTokenRevoked(ctx, id)
+}
+
+// Purge deletes the entries of tokens that have expired and returns how many were deleted
+func (store *PostgresRevocationStore) Purge(ctx context.Context) (int64, error) {
+	return store.querier.DeleteExpiredRevokedTokens(ctx)
+}
@@ -0,0 +1,40 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revocation store tests", func() {
+	It("Test revoke and check token", func() {
+		store := NewMemoryRevocationStore()
+		id := uuid.New()
+
+		revoked, err := store.IsRevoked(context.Background(), id)
+		Expect(err).To(BeNil())
+		Expect(revoked).To(BeFalse())
+
+		err = store.Revoke(context.Background(), id, time.Now().Add(time.Minute))
+		Expect(err).To(BeNil())
+
+		revoked, err = store.IsRevoked(context.Background(), id)
+		Expect(err).To(BeNil())
+		Expect(revoked).To(BeTrue())
+	})
+
+	It("Test expired entries are purged", func() {
+		store := NewMemoryRevocationStore()
+
+		err := store.Revoke(context.Background(), uuid.New(), time.Now().Add(-time.Minute))
+		Expect(err).To(BeNil())
+		Expect(store.Len()).To(Equal(1))
+
+		err = store.Revoke(context.Background(), uuid.New(), time.Now().Add(time.Minute))
+		Expect(err).To(BeNil())
+		Expect(store.Len()).To(Equal(1))
+	})
+})
@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Reloader is a cache that can read its source again, such as currency.PostgresRegistry
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// CurrencyReloader periodically reloads the currency registry,
+// so that the currencies changed through another server instance are picked up.
+type CurrencyReloader struct {
+	registry Reloader
+	interval time.Duration
+}
+
+// NewCurrencyReloader creates a new CurrencyReloader that runs at the given interval
+func NewCurrencyReloader(registry Reloader, interval time.Duration) *CurrencyReloader {
+	return &CurrencyReloader{
+		registry: registry,
+		interval: interval,
+	}
+}
+
+// Run reloads the registry at every interval until the context is done.
+// The registry keeps serving the currencies it has when a reload fails.
+func (reloader *CurrencyReloader) Run(ctx context.Context) {
+	runEvery(ctx, reloader.interval, func(ctx context.Context) {
+		if err := reloader.registry.Reload(ctx); err != nil {
+			log.Println("Cannot reload currencies with error: ", err)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Petatron/bank-simulator-backend/currency"
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Currency reloader tests", func() {
+	It("Test run picks up currencies added by another instance", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		usd := db.Currency{Code: string(model.USD), Exponent: 2, Symbol: "$", Enabled: true}
+		jpy := db.Currency{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true}
+
+		store := mockdb.NewMockStore(controller)
+		gomock.InOrder(
+			store.EXPECT().
+				ListCurrencies(gomock.Any()).
+				Times(1).
+				Return([]db.Currency{usd}, nil),
+			store.EXPECT().
+				ListCurrencies(gomock.Any()).
+				MinTimes(1).
+				DoAndReturn(func(context.Context) ([]db.Currency, error) {
+					cancel()
+					return []db.Currency{jpy, usd}, nil
+				}),
+		)
+
+		registry, err := currency.NewPostgresRegistry(context.Background(), store)
+		Expect(err).To(BeNil())
+		Expect(currency.IsSupported(registry, "JPY")).To(BeFalse())
+
+		done := make(chan struct{})
+		go func() {
+			NewCurrencyReloader(registry, time.Millisecond).Run(ctx)
+			close(done)
+		}()
+		Eventually(done, time.Second).Should(BeClosed())
+		Expect(currency.IsSupported(registry, "JPY")).To(BeTrue())
+	})
+})
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+)
+
+// HoldReleaser periodically releases the holds that have expired.
+// Expired holds already stop reserving funds when they pass, releasing them only updates their status.
+type HoldReleaser struct {
+	querier  db.Querier
+	interval time.Duration
+}
+
+// NewHoldReleaser creates a new HoldReleaser that runs at the given interval
+func NewHoldReleaser(querier db.Querier, interval time.Duration) *HoldReleaser {
+	return &HoldReleaser{
+		querier:  querier,
+		interval: interval,
+	}
+}
+
+// Run releases the expired holds at every interval until the context is done
+func (releaser *HoldReleaser) Run(ctx context.Context) {
+	runEvery(ctx, releaser.interval, func(ctx context.Context) {
+		if _, err := releaser.ReleaseExpired(ctx); err != nil {
+			log.Println("Cannot release expired holds with error: ", err)
+		}
+	})
+}
+
+// ReleaseExpired releases the holds that have expired and returns how many were released
+func (releaser *HoldReleaser) ReleaseExpired(ctx context.Context) (int64, error) {
+	return releaser.querier.ReleaseExpiredHolds(ctx)
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWorker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Unit Test for background workers")
+}
+
+var _ = Describe("Hold releaser tests", func() {
+	It("Test release expired holds", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			ReleaseExpiredHolds(gomock.Any()).
+			Times(1).
+			Return(int64(3), nil)
+
+		released, err := NewHoldReleaser(store, time.Minute).ReleaseExpired(context.Background())
+		Expect(err).To(BeNil())
+		Expect(released).To(Equal(int64(3)))
+	})
+
+	It("Test run releases holds until the context is done", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			ReleaseExpiredHolds(gomock.Any()).
+			MinTimes(1).
+			DoAndReturn(func(context.Context) (int64, error) {
+				cancel()
+				return 0, sql.ErrConnDone
+			})
+
+		done := make(chan struct{})
+		go func() {
+			NewHoldReleaser(store, time.Millisecond).Run(ctx)
+			close(done)
+		}()
+		Eventually(done, time.Second).Should(BeClosed())
+	})
+})
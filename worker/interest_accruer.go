@@ -0,0 +1,249 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/interest"
+	"github.com/Petatron/bank-simulator-backend/model"
+)
+
+// interestBatchSize is the maximum number of accounts read in one query
+const interestBatchSize = 100
+
+// InterestAccruer periodically accrues the daily interest of the accounts and posts it every month.
+// Days and months are calendar days and months in UTC of the clock the accruer is created with.
+type InterestAccruer struct {
+	store    db.Store
+	rates    interest.RateModel
+	clock    Clock
+	interval time.Duration
+	// accruedThrough is the last day accrued by this accruer, so that a run does not accrue the same days again
+	accruedThrough time.Time
+}
+
+// NewInterestAccruer creates a new InterestAccruer that runs at the given interval
+func NewInterestAccruer(store db.Store, rates interest.RateModel, clock Clock, interval time.Duration) *InterestAccruer {
+	return &InterestAccruer{
+		store:    store,
+		rates:    rates,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// Run accrues and posts the interest at every interval until the context is done
+func (accruer *InterestAccruer) Run(ctx context.Context) {
+	runEvery(ctx, accruer.interval, func(ctx context.Context) {
+		if err := accruer.RunOnce(ctx); err != nil {
+			log.Println("Cannot accrue interest with error: ", err)
+		}
+	})
+}
+
+// RunOnce accrues the interest of every completed day up to the current day of the clock that was not accrued yet,
+// then posts the interest of the previous month. Each day is recorded once it is accrued, and an accruer resumes
+// after the latest recorded day, or from the creation of the oldest interest bearing account if none is recorded,
+// so days missed while no accruer ran are caught up however many there are.
+// A month is posted as soon as all of its days are accrued.
+func (accruer *InterestAccruer) RunOnce(ctx context.Context) error {
+	today := startOfDay(accruer.clock.Now())
+	day, err := accruer.resumeDay(ctx, today)
+	if err != nil {
+		return err
+	}
+	if missed := int(today.Sub(day) / (24 * time.Hour)); missed > 1 {
+		log.Printf("Catching up %d days of interest from %s", missed, day.Format(time.DateOnly))
+	}
+
+	for ; day.Before(today); day = day.AddDate(0, 0, 1) {
+		if _, err := accruer.AccrueDay(ctx, day); err != nil {
+			return err
+		}
+		if err := accruer.store.CreateInterestAccrualRun(ctx, day); err != nil {
+			return err
+		}
+		accruer.accruedThrough = day
+
+		// The last day of a month that is caught up completes it
+		next := day.AddDate(0, 0, 1)
+		if next.Day() == 1 && next.Before(today) {
+			if _, err := accruer.PostMonth(ctx, day); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The previous month is posted again on every run to retry the accounts that failed
+	_, err = accruer.PostMonth(ctx, startOfMonth(today).AddDate(0, -1, 0))
+	return err
+}
+
+// resumeDay returns the first day that is not accrued yet. When the accruer has not accrued any day since it started,
+// it is the day after the latest recorded day, or the creation day of the oldest interest bearing account.
+// It returns today if there is nothing to accrue.
+func (accruer *InterestAccruer) resumeDay(ctx context.Context, today time.Time) (time.Time, error) {
+	if !accruer.accruedThrough.IsZero() {
+		return accruer.accruedThrough.AddDate(0, 0, 1), nil
+	}
+
+	latest, err := accruer.store.GetLatestInterestAccrualRun(ctx)
+	if err == nil {
+		return startOfDay(latest).AddDate(0, 0, 1), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return today, err
+	}
+
+	types := accruer.interestTypes()
+	if len(types) == 0 {
+		return today, nil
+	}
+	// Accounts are listed by id, so the first one is the oldest
+	accounts, err := accruer.store.ListAccountsByTypes(ctx, db.ListAccountsByTypesParams{
+		Types:         types,
+		CreatedBefore: today,
+		AfterID:       0,
+		Limit:         1,
+	})
+	if err != nil || len(accounts) == 0 {
+		return today, err
+	}
+	return startOfDay(accounts[0].CreatedAt), nil
+}
+
+// interestTypes returns the account types that earn interest
+func (accruer *InterestAccruer) interestTypes() []string {
+	var types []string
+	for _, accountType := range model.AccountTypes {
+		if !accruer.rates.Rate(accountType).IsZero() {
+			types = append(types, string(accountType))
+		}
+	}
+	return types
+}
+
+// AccrueDay accrues one day of interest for the accounts that are not closed and whose type earns interest,
+// and returns the accruals that were recorded. The interest accrues on the balance at the end of the day,
+// which is the sum of the entries written before midnight, so a late run accrues the same amounts as a timely one.
+// A day that was already accrued, or whose month was already posted, is skipped for the account.
+func (accruer *InterestAccruer) AccrueDay(ctx context.Context, day time.Time) ([]db.InterestAccrual, error) {
+	day = startOfDay(day)
+	endOfDay := day.AddDate(0, 0, 1)
+
+	types := accruer.interestTypes()
+	accruals := []db.InterestAccrual{}
+	if len(types) == 0 {
+		return accruals, nil
+	}
+
+	var afterID int64
+	for {
+		accounts, err := accruer.store.ListAccountsByTypes(ctx, db.ListAccountsByTypesParams{
+			Types:         types,
+			CreatedBefore: endOfDay,
+			AfterID:       afterID,
+			Limit:         interestBatchSize,
+		})
+		if err != nil {
+			return accruals, err
+		}
+
+		for _, account := range accounts {
+			balance, err := accruer.store.SumEntriesBefore(ctx, db.SumEntriesBeforeParams{
+				AccountID: account.ID,
+				Before:    endOfDay,
+			})
+			if err != nil {
+				return accruals, err
+			}
+
+			rate := accruer.rates.Rate(model.AccountType(account.Type))
+			amount, err := rate.DailyAccrual(balance)
+			if err != nil {
+				log.Printf("Cannot accrue interest of account %d with error: %v", account.ID, err)
+				continue
+			}
+			if amount == 0 {
+				continue
+			}
+
+			accrual, err := accruer.store.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+				AccountID:   account.ID,
+				AccrualDate: day,
+				Balance:     balance,
+				AnnualRate:  rate.String(),
+				Amount:      amount,
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return accruals, err
+			}
+			accruals = append(accruals, accrual)
+		}
+
+		if len(accounts) < interestBatchSize {
+			return accruals, nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+// PostMonth posts the interest accrued in the month of the given time for the accounts that were not posted yet,
+// and returns the postings that were recorded. It must only be called once every day of the month is accrued.
+// A failing account does not stop the others, its error is logged.
+func (accruer *InterestAccruer) PostMonth(ctx context.Context, month time.Time) ([]db.InterestPosting, error) {
+	period := startOfMonth(month)
+
+	postings := []db.InterestPosting{}
+	var afterID int64
+	for {
+		accountIDs, err := accruer.store.ListUnpostedInterestAccounts(ctx, db.ListUnpostedInterestAccountsParams{
+			PeriodStart:    period,
+			PeriodEnd:      period.AddDate(0, 1, 0),
+			AfterAccountID: afterID,
+			Limit:          interestBatchSize,
+		})
+		if err != nil {
+			return postings, err
+		}
+
+		for _, accountID := range accountIDs {
+			result, err := accruer.store.PostInterestTx(ctx, db.PostInterestTxParams{
+				AccountID: accountID,
+				Period:    period,
+			})
+			if err != nil {
+				// Another accruer may have posted it in the meantime
+				if !errors.Is(err, db.ErrInterestAlreadyPosted) {
+					log.Printf("Cannot post interest of account %d with error: %v", accountID, err)
+				}
+				continue
+			}
+			postings = append(postings, result.Posting)
+		}
+
+		if len(accountIDs) < interestBatchSize {
+			return postings, nil
+		}
+		afterID = accountIDs[len(accountIDs)-1]
+	}
+}
+
+// startOfDay returns midnight in UTC of the day of t in UTC
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfMonth returns midnight in UTC of the first day of the month of t in UTC
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
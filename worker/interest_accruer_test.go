@@ -0,0 +1,291 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	"github.com/Petatron/bank-simulator-backend/interest"
+	"github.com/Petatron/bank-simulator-backend/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+// fixedClock is a Clock that always tells the same time
+type fixedClock time.Time
+
+func (clock fixedClock) Now() time.Time {
+	return time.Time(clock)
+}
+
+var _ = Describe("Interest accruer tests", func() {
+	clock := fixedClock(time.Date(2026, time.October, 1, 10, 30, 0, 0, time.UTC))
+	september := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	october := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	newRates := func() interest.RateModel {
+		rates, err := interest.NewRateTable(interest.DefaultRates)
+		Expect(err).To(BeNil())
+		return rates
+	}
+
+	It("Test accrue day", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		day := time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC)
+		store.EXPECT().
+			ListAccountsByTypes(gomock.Any(), gomock.Eq(db.ListAccountsByTypesParams{
+				Types:         []string{string(model.AccountTypeSavings)},
+				CreatedBefore: october,
+				AfterID:       0,
+				Limit:         interestBatchSize,
+			})).
+			Times(1).
+			Return([]db.Account{
+				{ID: 1, Type: string(model.AccountTypeSavings)},
+				{ID: 2, Type: string(model.AccountTypeSavings)},
+				{ID: 3, Type: string(model.AccountTypeSavings)},
+			}, nil)
+		store.EXPECT().
+			SumEntriesBefore(gomock.Any(), gomock.Eq(db.SumEntriesBeforeParams{AccountID: 1, Before: october})).
+			Times(1).
+			Return(int64(100000), nil)
+		store.EXPECT().
+			CreateInterestAccrual(gomock.Any(), gomock.Eq(db.CreateInterestAccrualParams{
+				AccountID:   1,
+				AccrualDate: day,
+				Balance:     100000,
+				AnnualRate:  "0.02",
+				Amount:      5479452,
+			})).
+			Times(1).
+			Return(db.InterestAccrual{ID: 10, AccountID: 1, Amount: 5479452}, nil)
+		// An empty account accrues nothing
+		store.EXPECT().
+			SumEntriesBefore(gomock.Any(), gomock.Eq(db.SumEntriesBeforeParams{AccountID: 2, Before: october})).
+			Times(1).
+			Return(int64(0), nil)
+		store.EXPECT().
+			CreateInterestAccrual(gomock.Any(), gomock.Cond(func(arg db.CreateInterestAccrualParams) bool {
+				return arg.AccountID == 2
+			})).
+			Times(0)
+		// A day that was already accrued is left out
+		store.EXPECT().
+			SumEntriesBefore(gomock.Any(), gomock.Eq(db.SumEntriesBeforeParams{AccountID: 3, Before: october})).
+			Times(1).
+			Return(int64(100000), nil)
+		store.EXPECT().
+			CreateInterestAccrual(gomock.Any(), gomock.Cond(func(arg db.CreateInterestAccrualParams) bool {
+				return arg.AccountID == 3
+			})).
+			Times(1).
+			Return(db.InterestAccrual{}, sql.ErrNoRows)
+
+		// Any time of the day accrues the whole day
+		accruals, err := NewInterestAccruer(store, newRates(), clock, time.Hour).AccrueDay(context.Background(), day.Add(15*time.Hour))
+		Expect(err).To(BeNil())
+		Expect(accruals).To(HaveLen(1))
+		Expect(accruals[0].ID).To(Equal(int64(10)))
+	})
+
+	It("Test accrue day without interest bearing account types", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			ListAccountsByTypes(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		rates, err := interest.NewRateTable(nil)
+		Expect(err).To(BeNil())
+
+		accruals, err := NewInterestAccruer(store, rates, clock, time.Hour).AccrueDay(context.Background(), september)
+		Expect(err).To(BeNil())
+		Expect(accruals).To(BeEmpty())
+	})
+
+	It("Test post month", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			ListUnpostedInterestAccounts(gomock.Any(), gomock.Eq(db.ListUnpostedInterestAccountsParams{
+				PeriodStart:    september,
+				PeriodEnd:      october,
+				AfterAccountID: 0,
+				Limit:          interestBatchSize,
+			})).
+			Times(1).
+			Return([]int64{1, 2, 3}, nil)
+		store.EXPECT().
+			PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{AccountID: 1, Period: september})).
+			Times(1).
+			Return(db.PostInterestTxResult{Posting: db.InterestPosting{ID: 20, AccountID: 1}}, nil)
+		// An account that was posted in the meantime is left out
+		store.EXPECT().
+			PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{AccountID: 2, Period: september})).
+			Times(1).
+			Return(db.PostInterestTxResult{}, fmt.Errorf("%w: account 2 for 2026-09", db.ErrInterestAlreadyPosted))
+		// A failing account does not stop the others
+		store.EXPECT().
+			PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{AccountID: 3, Period: september})).
+			Times(1).
+			Return(db.PostInterestTxResult{}, sql.ErrConnDone)
+
+		postings, err := NewInterestAccruer(store, newRates(), clock, time.Hour).PostMonth(context.Background(), september.AddDate(0, 0, 29))
+		Expect(err).To(BeNil())
+		Expect(postings).To(HaveLen(1))
+		Expect(postings[0].ID).To(Equal(int64(20)))
+	})
+
+	It("Test run catches up from the latest accrued day and posts the previous month", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		// The accruer last ran 20 days ago, every missed day is accrued
+		latest := october.AddDate(0, 0, -21)
+		store.EXPECT().
+			GetLatestInterestAccrualRun(gomock.Any()).
+			Times(1).
+			Return(latest, nil)
+		var accrued, recorded []time.Time
+		store.EXPECT().
+			ListAccountsByTypes(gomock.Any(), gomock.Any()).
+			Times(20).
+			DoAndReturn(func(_ context.Context, arg db.ListAccountsByTypesParams) ([]db.Account, error) {
+				accrued = append(accrued, arg.CreatedBefore)
+				return []db.Account{}, nil
+			})
+		store.EXPECT().
+			CreateInterestAccrualRun(gomock.Any(), gomock.Any()).
+			Times(20).
+			DoAndReturn(func(_ context.Context, day time.Time) error {
+				recorded = append(recorded, day)
+				return nil
+			})
+		store.EXPECT().
+			ListUnpostedInterestAccounts(gomock.Any(), gomock.Cond(func(arg db.ListUnpostedInterestAccountsParams) bool {
+				return arg.PeriodStart.Equal(september) && arg.PeriodEnd.Equal(october)
+			})).
+			Times(2).
+			Return([]int64{}, nil)
+
+		accruer := NewInterestAccruer(store, newRates(), clock, time.Hour)
+		Expect(accruer.RunOnce(context.Background())).To(BeNil())
+		Expect(accrued).To(HaveLen(20))
+		Expect(accrued[0]).To(Equal(latest.AddDate(0, 0, 2)))
+		Expect(accrued[19]).To(Equal(october))
+		Expect(recorded[0]).To(Equal(latest.AddDate(0, 0, 1)))
+		Expect(recorded[19]).To(Equal(september.AddDate(0, 0, 29)))
+
+		// The days that were accrued are not accrued again on the same day
+		Expect(accruer.RunOnce(context.Background())).To(BeNil())
+		Expect(accrued).To(HaveLen(20))
+	})
+
+	It("Test run posts every month it catches up", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		july := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+		august := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+		store.EXPECT().
+			GetLatestInterestAccrualRun(gomock.Any()).
+			Times(1).
+			Return(july.AddDate(0, 0, 29), nil)
+		store.EXPECT().
+			ListAccountsByTypes(gomock.Any(), gomock.Any()).
+			Times(62).
+			Return([]db.Account{}, nil)
+		store.EXPECT().
+			CreateInterestAccrualRun(gomock.Any(), gomock.Any()).
+			Times(62)
+
+		var posted []time.Time
+		store.EXPECT().
+			ListUnpostedInterestAccounts(gomock.Any(), gomock.Any()).
+			Times(3).
+			DoAndReturn(func(_ context.Context, arg db.ListUnpostedInterestAccountsParams) ([]int64, error) {
+				posted = append(posted, arg.PeriodStart)
+				return []int64{}, nil
+			})
+
+		Expect(NewInterestAccruer(store, newRates(), clock, time.Hour).RunOnce(context.Background())).To(BeNil())
+		Expect(posted).To(Equal([]time.Time{july, august, september}))
+	})
+
+	It("Test first run starts from the oldest interest bearing account", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			GetLatestInterestAccrualRun(gomock.Any()).
+			Times(1).
+			Return(time.Time{}, sql.ErrNoRows)
+		store.EXPECT().
+			ListAccountsByTypes(gomock.Any(), gomock.Eq(db.ListAccountsByTypesParams{
+				Types:         []string{string(model.AccountTypeSavings)},
+				CreatedBefore: october,
+				AfterID:       0,
+				Limit:         1,
+			})).
+			Times(1).
+			Return([]db.Account{{ID: 1, CreatedAt: october.Add(-36 * time.Hour)}}, nil)
+		var accrued []time.Time
+		store.EXPECT().
+			ListAccountsByTypes(gomock.Any(), gomock.Cond(func(arg db.ListAccountsByTypesParams) bool {
+				return arg.Limit == interestBatchSize
+			})).
+			Times(2).
+			DoAndReturn(func(_ context.Context, arg db.ListAccountsByTypesParams) ([]db.Account, error) {
+				accrued = append(accrued, arg.CreatedBefore)
+				return []db.Account{}, nil
+			})
+		store.EXPECT().
+			CreateInterestAccrualRun(gomock.Any(), gomock.Any()).
+			Times(2)
+		store.EXPECT().
+			ListUnpostedInterestAccounts(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return([]int64{}, nil)
+
+		Expect(NewInterestAccruer(store, newRates(), clock, time.Hour).RunOnce(context.Background())).To(BeNil())
+		Expect(accrued).To(Equal([]time.Time{september.AddDate(0, 0, 29), october}))
+	})
+
+	It("Test run does not post after a failed accrual", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			GetLatestInterestAccrualRun(gomock.Any()).
+			Times(1).
+			Return(september.AddDate(0, 0, 28), nil)
+		store.EXPECT().
+			ListAccountsByTypes(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil, sql.ErrConnDone)
+		store.EXPECT().
+			CreateInterestAccrualRun(gomock.Any(), gomock.Any()).
+			Times(0)
+		store.EXPECT().
+			ListUnpostedInterestAccounts(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		err := NewInterestAccruer(store, newRates(), clock, time.Hour).RunOnce(context.Background())
+		Expect(err).To(Equal(sql.ErrConnDone))
+	})
+})
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+)
+
+// scheduledTransferBatchSize is the maximum number of due scheduled transfers executed in one run
+const scheduledTransferBatchSize = 100
+
+// ScheduledTransferRunner periodically executes the scheduled transfers that are due
+type ScheduledTransferRunner struct {
+	store    db.Store
+	interval time.Duration
+}
+
+// NewScheduledTransferRunner creates a new ScheduledTransferRunner that runs at the given interval
+func NewScheduledTransferRunner(store db.Store, interval time.Duration) *ScheduledTransferRunner {
+	return &ScheduledTransferRunner{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Run executes the due scheduled transfers at every interval until the context is done
+func (runner *ScheduledTransferRunner) Run(ctx context.Context) {
+	runEvery(ctx, runner.interval, func(ctx context.Context) {
+		if _, err := runner.ExecuteDue(ctx, time.Now()); err != nil {
+			log.Println("Cannot execute scheduled transfers with error: ", err)
+		}
+	})
+}
+
+// ExecuteDue executes the scheduled transfers that are due at now and returns the runs that were recorded.
+// A failing scheduled transfer does not stop the others, its error is logged.
+func (runner *ScheduledTransferRunner) ExecuteDue(ctx context.Context, now time.Time) ([]db.ScheduledTransferRun, error) {
+	due, err := runner.store.ListDueScheduledTransfers(ctx, db.ListDueScheduledTransfersParams{
+		Now:        now,
+		LimitCount: scheduledTransferBatchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []db.ScheduledTransferRun{}
+	for _, scheduled := range due {
+		result, err := runner.store.ExecuteScheduledTransferTx(ctx, db.ExecuteScheduledTransferTxParams{
+			ID:  scheduled.ID,
+			Now: now,
+		})
+		if err != nil {
+			// Another runner may have executed it, or its owner paused or cancelled it in the meantime
+			if !errors.Is(err, db.ErrScheduledTransferNotDue) {
+				log.Printf("Cannot execute scheduled transfer %d with error: %v", scheduled.ID, err)
+			}
+			continue
+		}
+		runs = append(runs, result.Run)
+	}
+
+	return runs, nil
+}
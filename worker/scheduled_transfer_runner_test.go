@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	mockdb "github.com/Petatron/bank-simulator-backend/db/mock"
+	db "github.com/Petatron/bank-simulator-backend/db/sqlc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Scheduled transfer runner tests", func() {
+	now := time.Now()
+
+	It("Test execute due scheduled transfers", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		due := []db.ScheduledTransfer{{ID: 1}, {ID: 2}, {ID: 3}}
+		store.EXPECT().
+			ListDueScheduledTransfers(gomock.Any(), gomock.Eq(db.ListDueScheduledTransfersParams{
+				Now:        now,
+				LimitCount: scheduledTransferBatchSize,
+			})).
+			Times(1).
+			Return(due, nil)
+		store.EXPECT().
+			ExecuteScheduledTransferTx(gomock.Any(), gomock.Eq(db.ExecuteScheduledTransferTxParams{ID: 1, Now: now})).
+			Times(1).
+			Return(db.ExecuteScheduledTransferTxResult{Run: db.ScheduledTransferRun{ID: 10, Outcome: db.ScheduledTransferRunSucceeded}}, nil)
+		// A scheduled transfer that is no longer due is left out
+		store.EXPECT().
+			ExecuteScheduledTransferTx(gomock.Any(), gomock.Eq(db.ExecuteScheduledTransferTxParams{ID: 2, Now: now})).
+			Times(1).
+			Return(db.ExecuteScheduledTransferTxResult{}, fmt.Errorf("%w: scheduled transfer 2", db.ErrScheduledTransferNotDue))
+		// A failing scheduled transfer does not stop the others
+		store.EXPECT().
+			ExecuteScheduledTransferTx(gomock.Any(), gomock.Eq(db.ExecuteScheduledTransferTxParams{ID: 3, Now: now})).
+			Times(1).
+			Return(db.ExecuteScheduledTransferTxResult{}, sql.ErrConnDone)
+
+		runs, err := NewScheduledTransferRunner(store, time.Minute).ExecuteDue(context.Background(), now)
+		Expect(err).To(BeNil())
+		Expect(runs).To(HaveLen(1))
+		Expect(runs[0].ID).To(Equal(int64(10)))
+	})
+
+	It("Test list due scheduled transfers error", func() {
+		controller := gomock.NewController(GinkgoT())
+		defer controller.Finish()
+
+		store := mockdb.NewMockStore(controller)
+		store.EXPECT().
+			ListDueScheduledTransfers(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil, sql.ErrConnDone)
+		store.EXPECT().
+			ExecuteScheduledTransferTx(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		_, err := NewScheduledTransferRunner(store, time.Minute).ExecuteDue(context.Background(), now)
+		Expect(err).To(Equal(sql.ErrConnDone))
+	})
+})
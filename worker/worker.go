@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// runEvery calls fn at every interval until the context is done
+func runEvery(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn(ctx)
+		}
+	}
+}
+
+// Clock tells the current time. Workers that act on calendar days take one so that tests can run them on any day.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock that reads the system time
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+// Now returns the current system time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}